@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xeloxa/s3finder/pkg/extract"
+)
+
+// newExtractCmd returns the "extract" command.
+func newExtractCmd() *cobra.Command {
+	var (
+		out    string
+		format string
+	)
+	cmd := &cobra.Command{
+		Use:   "extract <path>...",
+		Short: "Extract bucket names from source trees, bundles, HAR/Burp captures and app packages",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" && format != "names" {
+				return fmt.Errorf("invalid format %q (valid: text, json, names)", format)
+			}
+
+			var refs []extract.Reference
+			if err := extract.Paths(args, extract.Options{}, func(r extract.Reference) {
+				refs = append(refs, r)
+			}); err != nil {
+				return err
+			}
+
+			w := io.Writer(os.Stdout)
+			if out != "" {
+				f, err := os.Create(out)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+
+			buckets := uniqueBuckets(refs)
+			switch format {
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				if refs == nil {
+					refs = []extract.Reference{}
+				}
+				if err := enc.Encode(refs); err != nil {
+					return err
+				}
+			case "names":
+				for _, b := range buckets {
+					fmt.Fprintln(w, b)
+				}
+			default:
+				for _, r := range refs {
+					fmt.Fprintf(w, "%s\t%s\n", r.Bucket, r.Location())
+				}
+			}
+
+			fmt.Fprintf(os.Stderr, "Found %d reference(s) to %d bucket(s)\n", len(refs), len(buckets))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&out, "output", "o", "", "Write results to a file instead of stdout")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text (bucket and file:line), json or names")
+	return cmd
+}
+
+// extractBuckets returns the unique bucket names referenced under paths.
+func extractBuckets(paths []string) ([]string, error) {
+	var refs []extract.Reference
+	err := extract.Paths(paths, extract.Options{}, func(r extract.Reference) {
+		refs = append(refs, r)
+	})
+	return uniqueBuckets(refs), err
+}
+
+// uniqueBuckets returns the bucket names of refs in order of first
+// reference.
+func uniqueBuckets(refs []extract.Reference) []string {
+	seen := make(map[string]struct{})
+	var buckets []string
+	for _, r := range refs {
+		if _, ok := seen[r.Bucket]; !ok {
+			seen[r.Bucket] = struct{}{}
+			buckets = append(buckets, r.Bucket)
+		}
+	}
+	return buckets
+}
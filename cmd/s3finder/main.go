@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -13,8 +15,12 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/xeloxa/s3finder/internal/config"
 	"github.com/xeloxa/s3finder/pkg/ai"
+	"github.com/xeloxa/s3finder/pkg/dns"
+	"github.com/xeloxa/s3finder/pkg/mirror"
+	"github.com/xeloxa/s3finder/pkg/model"
 	"github.com/xeloxa/s3finder/pkg/output"
 	"github.com/xeloxa/s3finder/pkg/permutation"
+	"github.com/xeloxa/s3finder/pkg/rank"
 	"github.com/xeloxa/s3finder/pkg/recon"
 	"github.com/xeloxa/s3finder/pkg/scanner"
 )
@@ -36,7 +42,8 @@ Examples:
   s3finder -s acme                    # Scan with permutations of "acme"
   s3finder -s acme -w wordlist.txt    # Scan with wordlist + permutations
   s3finder -s acme --ai               # Enable AI name generation
-  s3finder -s acme -t 200 --rps 1000  # High-speed scan`,
+  s3finder -s acme -t 200 --rps 1000  # High-speed scan
+  s3finder -s acme --download         # Mirror public buckets after the scan`,
 		RunE: run,
 	}
 
@@ -45,12 +52,67 @@ Examples:
 	rootCmd.Flags().Float64Var(&cfg.MaxRPS, "rps", cfg.MaxRPS, "Maximum requests per second")
 	rootCmd.Flags().IntVar(&cfg.Timeout, "timeout", cfg.Timeout, "Request timeout in seconds")
 	rootCmd.Flags().BoolVar(&cfg.DeepInspect, "deep", cfg.DeepInspect, "Perform deep inspection on found buckets")
+	rootCmd.Flags().IntVar(&cfg.InspectWorkers, "inspect-workers", cfg.InspectWorkers, "Number of concurrent deep inspection workers")
+	rootCmd.Flags().Float64Var(&cfg.InspectRPS, "inspect-rps", cfg.InspectRPS, "Maximum deep inspection requests per second")
+	rootCmd.Flags().BoolVar(&cfg.ProbeKeys, "probe-keys", cfg.ProbeKeys, "HEAD known object keys in buckets that deny listing")
+	rootCmd.Flags().StringVar(&cfg.KeysFile, "keys-file", "", "Object key wordlist for --probe-keys (default: built-in list)")
+	rootCmd.Flags().BoolVar(&cfg.PeekArchives, "peek-archives", cfg.PeekArchives, "List files inside ZIP and tar.gz objects in public buckets")
 
 	// Input flags
-	rootCmd.Flags().StringVarP(&cfg.Seed, "seed", "s", "", "Target keyword for bucket name generation")
+	rootCmd.Flags().StringArrayVarP(&cfg.Seeds, "seed", "s", nil, "Target keyword for bucket name generation (repeatable)")
+	rootCmd.Flags().StringVar(&cfg.SeedsFile, "seeds-file", "", "File with one seed keyword per line")
+	rootCmd.Flags().BoolVar(&cfg.CombineSeeds, "combine-seeds", cfg.CombineSeeds, "Also combine seeds with each other (acme-rocket, rocket.acme)")
+	rootCmd.Flags().IntVar(&cfg.ComboLimit, "combo-limit", cfg.ComboLimit, "Maximum names generated from seed combinations (0 = unlimited)")
 	rootCmd.Flags().StringVarP(&cfg.Wordlist, "wordlist", "w", "", "Path to wordlist file")
 	rootCmd.Flags().StringVarP(&cfg.Domain, "domain", "d", "", "Target domain for CT log subdomain discovery")
+	rootCmd.Flags().StringVar(&cfg.SubdomainsFile, "subdomains-file", "", "Subdomains from a plain list or amass, subfinder or massdns output")
+	rootCmd.Flags().StringArrayVar(&cfg.Extract, "extract", nil, "Scan bucket names referenced in these files or directories (repeatable)")
 	rootCmd.Flags().IntVar(&cfg.CTLimit, "ct-limit", cfg.CTLimit, "Maximum subdomains to fetch from CT logs")
+	rootCmd.Flags().IntVar(&cfg.CTWords, "ct-words", cfg.CTWords, "Maximum subdomain words to permute, most frequent first (0 = unlimited)")
+	rootCmd.Flags().StringSliceVar(&cfg.ReconSources, "recon-sources", cfg.ReconSources, "Subdomain sources queried in parallel: crtsh, certspotter, censys")
+	rootCmd.Flags().IntVar(&cfg.ReconTimeout, "recon-timeout", cfg.ReconTimeout, "Timeout per recon source in seconds")
+	rootCmd.Flags().BoolVar(&cfg.CTExcludeExpired, "ct-exclude-expired", cfg.CTExcludeExpired, "Ignore expired certificates in crt.sh results")
+	rootCmd.Flags().DurationVar(&cfg.CTCacheTTL, "ct-cache-ttl", cfg.CTCacheTTL, "Reuse cached crt.sh results for this long (0 = no cache)")
+	rootCmd.Flags().StringVar(&cfg.CTCacheDir, "ct-cache-dir", "", "Directory for cached crt.sh results (default: user cache dir)")
+	rootCmd.Flags().StringVar(&cfg.CertSpotterKey, "certspotter-key", "", "CertSpotter API key (or use env: CERTSPOTTER_API_KEY)")
+	rootCmd.Flags().StringVar(&cfg.CensysID, "censys-id", "", "Censys API ID (or use env: CENSYS_API_ID)")
+	rootCmd.Flags().StringVar(&cfg.CensysSecret, "censys-secret", "", "Censys API secret (or use env: CENSYS_API_SECRET)")
+	rootCmd.Flags().StringVar(&cfg.CensysURL, "censys-url", "", "Censys-compatible API base URL (default: search.censys.io)")
+	rootCmd.Flags().BoolVar(&cfg.CNAMESweep, "cname-sweep", cfg.CNAMESweep, "Resolve subdomain CNAMEs and scan buckets behind S3 targets (one DNS lookup per subdomain hop)")
+	rootCmd.Flags().BoolVar(&cfg.Wayback, "wayback", cfg.Wayback, "Search archived URLs of --domain for bucket references and path words")
+	rootCmd.Flags().StringVar(&cfg.WaybackURL, "wayback-url", "", "CDX server base URL (default: web.archive.org)")
+	rootCmd.Flags().IntVar(&cfg.WaybackPages, "wayback-pages", cfg.WaybackPages, "Maximum CDX pages of 1000 URLs to fetch")
+	rootCmd.Flags().Float64Var(&cfg.WaybackRPS, "wayback-rps", cfg.WaybackRPS, "Maximum CDX requests per second")
+	rootCmd.Flags().StringVar(&cfg.CodeSearch, "code-search", "", "Search public code for bucket names: github or gitlab")
+	rootCmd.Flags().StringVar(&cfg.CodeSearchToken, "code-search-token", "", "Code search API token (or use env: GITHUB_TOKEN, GITLAB_TOKEN)")
+	rootCmd.Flags().StringVar(&cfg.CodeSearchURL, "code-search-url", "", "GitHub- or GitLab-compatible API base URL")
+	rootCmd.Flags().IntVar(&cfg.CodeSearchPages, "code-search-pages", cfg.CodeSearchPages, "Pages of 100 results per code search query")
+	rootCmd.Flags().StringVar(&cfg.Crawl, "crawl", "", "Crawl this website for bucket references")
+	rootCmd.Flags().IntVar(&cfg.CrawlDepth, "crawl-depth", cfg.CrawlDepth, "Links followed from the crawl start page")
+	rootCmd.Flags().IntVar(&cfg.CrawlPages, "crawl-pages", cfg.CrawlPages, "Maximum pages fetched by the crawler")
+	rootCmd.Flags().BoolVar(&cfg.CrawlRobots, "crawl-robots", cfg.CrawlRobots, "Skip paths disallowed by robots.txt while crawling")
+
+	// Permutation flags
+	rootCmd.Flags().StringVar(&cfg.PermProfile, "perm-profile", cfg.PermProfile, "Permutation profile: "+strings.Join(permutation.ProfileNames(), ", ")+", or a YAML/TXT file")
+	rootCmd.Flags().IntVar(&cfg.YearsBack, "years-back", cfg.YearsBack, "Generate dates this many years before today")
+	rootCmd.Flags().IntVar(&cfg.YearsForward, "years-forward", cfg.YearsForward, "Generate dates this many years after today")
+	rootCmd.Flags().StringSliceVar(&cfg.DateFormats, "date-formats", nil, "Date formats: "+strings.Join(permutation.DateFormats, ", ")+" (default: profile's)")
+	rootCmd.Flags().StringVar(&cfg.Mask, "mask", "", "Hashcat-style mask, e.g. 'acme-?l?l?d' (?l a-z, ?d 0-9, ?h hex, ?a a-z0-9, ?s -., ?1-?4 custom)")
+	rootCmd.Flags().StringArrayVar(&cfg.MaskCharsets, "mask-charset", nil, "Custom mask charset as N=chars, e.g. 1=?l?d (repeatable)")
+	rootCmd.Flags().BoolVar(&cfg.MaskIncrement, "mask-increment", cfg.MaskIncrement, "Also try shorter prefixes of the mask")
+	rootCmd.Flags().IntVar(&cfg.MaskIncrementMin, "mask-increment-min", cfg.MaskIncrementMin, "Shortest mask prefix length with --mask-increment")
+	rootCmd.Flags().IntVar(&cfg.ExpandDepth, "expand-depth", cfg.ExpandDepth, "Feed found buckets back into the engine for this many extra waves")
+	rootCmd.Flags().IntVar(&cfg.ExpandLimit, "expand-limit", cfg.ExpandLimit, "Maximum candidates per expansion wave (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&cfg.ExpandAI, "expand-ai", cfg.ExpandAI, "Also ask the AI provider for names during expansion (requires --ai)")
+	rootCmd.Flags().StringVar(&cfg.Model, "model", "", "n-gram model file from 'model train' to mix into generated names")
+	rootCmd.Flags().IntVar(&cfg.ModelCount, "model-count", cfg.ModelCount, "Number of model-generated names per seed")
+	rootCmd.Flags().StringArrayVar(&cfg.Templates, "template", nil, "Naming template, e.g. '{env}{sep}{seed}{sep}{purpose?}' (repeatable)")
+	rootCmd.Flags().StringArrayVar(&cfg.Dicts, "dict", nil, "Template dictionary as name=path to a wordlist (repeatable)")
+	rootCmd.Flags().StringSliceVar(&cfg.Mutators, "mutators", cfg.Mutators, "Seed mutators: abbrev, plural, vowels, leet, typo or all (default none)")
+	rootCmd.Flags().IntVar(&cfg.MutateLimit, "mutate-limit", cfg.MutateLimit, "Maximum mutated seed variants per seed, each permuted with prefixes and suffixes (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&cfg.Rank, "rank", cfg.Rank, "Scan the most likely names first")
+	rootCmd.Flags().IntVar(&cfg.Budget, "budget", cfg.Budget, "Maximum number of names to scan, most likely first (0 = unlimited)")
+	rootCmd.Flags().DurationVar(&cfg.MaxDuration, "max-duration", cfg.MaxDuration, "Stop scanning after this long, e.g. 30m (0 = unlimited)")
 
 	// AI flags
 	rootCmd.Flags().BoolVar(&cfg.AIEnabled, "ai", cfg.AIEnabled, "Enable AI-powered name generation")
@@ -66,6 +128,50 @@ Examples:
 	rootCmd.Flags().BoolVar(&cfg.NoColor, "no-color", cfg.NoColor, "Disable colored output")
 	rootCmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", cfg.Verbose, "Verbose output")
 
+	// Download flags
+	rootCmd.Flags().BoolVar(&cfg.Download, "download", cfg.Download, "Mirror public buckets to disk after the scan")
+	addDownloadFlags(rootCmd)
+
+	// Download command
+	downloadCmd := &cobra.Command{
+		Use:   "download <bucket>",
+		Short: "Anonymously mirror a public bucket to disk",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			return mirrorBucket(ctx, args[0])
+		},
+	}
+	addDownloadFlags(downloadCmd)
+	rootCmd.AddCommand(downloadCmd)
+
+	// Model command
+	rootCmd.AddCommand(newModelCmd())
+
+	// Extract command
+	rootCmd.AddCommand(newExtractCmd())
+
+	// Profiles command
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "profiles",
+		Short: "List permutation profiles with expected names per seed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, name := range permutation.ProfileNames() {
+				profile, err := permutation.LoadProfile(name)
+				if err != nil {
+					return err
+				}
+				engine, err := profile.Engine()
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%-18s ~%-6d %s\n", name, engine.Estimate(), profile.Description)
+			}
+			return nil
+		},
+	})
+
 	// Version command
 	rootCmd.AddCommand(&cobra.Command{
 		Use:   "version",
@@ -81,9 +187,40 @@ Examples:
 }
 
 func run(cmd *cobra.Command, args []string) error {
+	if cfg.SeedsFile != "" {
+		seeds, err := config.LoadWordlist(cfg.SeedsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load seeds file: %w", err)
+		}
+		cfg.Seeds = append(cfg.Seeds, seeds...)
+	}
+	// Company names become slug, concatenated and acronym seeds, and
+	// non-ASCII seeds are transliterated
+	var rewritten []string
+	for _, seed := range cfg.Seeds {
+		if permutation.IsCompanyName(seed) {
+			rewritten = append(rewritten, strings.TrimSpace(seed))
+		}
+	}
+	cfg.Seeds = permutation.ExpandSeeds(cfg.Seeds)
+
 	// Validate input sources
-	if cfg.Seed == "" && cfg.Wordlist == "" && cfg.Domain == "" && !cfg.AIEnabled {
-		return fmt.Errorf("at least one input source is required: --seed, --wordlist, --domain, or --ai")
+	if len(cfg.Seeds) == 0 && cfg.Wordlist == "" && cfg.Domain == "" && cfg.SubdomainsFile == "" && len(cfg.Extract) == 0 && cfg.Crawl == "" && cfg.Mask == "" && !cfg.AIEnabled {
+		return fmt.Errorf("at least one input source is required: --seed, --wordlist, --domain, --subdomains-file, --extract, --crawl, --mask, or --ai")
+	}
+	if cfg.Wayback && cfg.Domain == "" {
+		return fmt.Errorf("--wayback requires --domain")
+	}
+	if cfg.CodeSearch != "" && cfg.Domain == "" && len(cfg.Seeds) == 0 {
+		return fmt.Errorf("--code-search requires --domain or --seed")
+	}
+
+	var mask *permutation.Mask
+	if cfg.Mask != "" {
+		var err error
+		if mask, err = newMask(); err != nil {
+			return err
+		}
 	}
 
 	// Setup context with cancellation
@@ -114,37 +251,59 @@ func run(cmd *cobra.Command, args []string) error {
 	// Banner (Static)
 	printBanner()
 
+	for _, seed := range rewritten {
+		fmt.Printf("Seed %q normalized to: %s\n", seed, strings.Join(permutation.ExpandSeeds([]string{seed}), ", "))
+	}
+
+	engine, err := newEngine()
+	if err != nil {
+		return err
+	}
+
+	var nameModel *model.Model
+	if cfg.Model != "" {
+		if nameModel, err = model.Load(cfg.Model); err != nil {
+			return fmt.Errorf("failed to load model: %w", err)
+		}
+	}
+	scorer := rank.NewScorer(nameModel, cfg.Seeds)
+
 	// Generate bucket names
-	names, err := generateNames(ctx)
+	candidates, err := generateNames(ctx, engine, scorer)
 	if err != nil {
 		return fmt.Errorf("failed to generate names: %w", err)
 	}
 
-	if len(names) == 0 {
-		return fmt.Errorf("no bucket names generated")
+	// Most likely names first, then cut to the budget
+	if cfg.Rank {
+		scorer.Rank(candidates)
 	}
+	if cfg.Budget > 0 && len(candidates) > cfg.Budget {
+		fmt.Printf("Budget: scanning the top %d of %d candidates\n", cfg.Budget, len(candidates))
+		candidates = candidates[:cfg.Budget]
+	}
+	names := rank.Names(candidates)
 
-	fmt.Printf("Generated %d unique bucket names to scan\n\n", len(names))
-
-	// Setup progress bar
-	progress := output.NewProgress(&output.ProgressConfig{
-		Output:      os.Stderr,
-		Total:       int64(len(names)),
-		RefreshRate: 100 * time.Millisecond,
-		ShowRPS:     true,
-		UseColors:   !cfg.NoColor,
-		BarWidth:    25,
-		ExternalMu:  &outputMu,
-	})
+	if len(names) == 0 && mask == nil {
+		return fmt.Errorf("no bucket names generated")
+	}
 
-	// Setup output writers (pass progress for coordinated output)
-	realtimeWriter := output.NewRealtime(&output.RealtimeConfig{
-		Output:    os.Stdout,
-		UseColors: !cfg.NoColor,
-		UseLinks:  !cfg.NoColor, // Enable clickable links when colors are enabled
-		Verbose:   cfg.Verbose,
-		Progress:  progress, // Coordinate output with progress bar
-	})
+	total := int64(len(names))
+	if mask != nil {
+		keyspace := mask.Keyspace()
+		fmt.Printf("Mask %s: keyspace %s candidates (invalid bucket names are skipped)\n", mask, keyspace)
+		if keyspace.IsInt64() && total+keyspace.Int64() >= total {
+			total += keyspace.Int64()
+		} else {
+			total = math.MaxInt64
+		}
+		if cfg.Budget > 0 {
+			total = min(total, int64(cfg.Budget))
+		}
+		fmt.Printf("Generated %d unique bucket names plus mask candidates to scan\n\n", len(names))
+	} else {
+		fmt.Printf("Generated %d unique bucket names to scan\n\n", len(names))
+	}
 
 	reportWriter, err := output.NewReport(&output.ReportConfig{
 		FilePath:  cfg.OutputFile,
@@ -156,117 +315,487 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 	defer reportWriter.Close()
 
-	multiWriter := output.NewMultiWriter(realtimeWriter, reportWriter)
+	// Known object keys for buckets that deny listing
+	var objectKeys []string
+	if cfg.ProbeKeys {
+		if cfg.KeysFile != "" {
+			objectKeys, err = config.LoadWordlist(cfg.KeysFile)
+			if err != nil {
+				return fmt.Errorf("failed to load keys file: %w", err)
+			}
+		} else {
+			objectKeys = scanner.DefaultObjectKeys(permutation.DateRange{
+				Back:    cfg.YearsBack,
+				Forward: cfg.YearsForward,
+				Formats: []string{permutation.DateYYYY},
+			}.Values())
+		}
+	}
 
-	// Create scanner
-	s := scanner.New(&scanner.Config{
-		Workers:     cfg.Workers,
-		MaxRPS:      cfg.MaxRPS,
-		Timeout:     time.Duration(cfg.Timeout) * time.Second,
-		DeepInspect: cfg.DeepInspect,
-	})
+	scanCfg := &scanner.Config{
+		Workers:        cfg.Workers,
+		MaxRPS:         cfg.MaxRPS,
+		Timeout:        time.Duration(cfg.Timeout) * time.Second,
+		DeepInspect:    cfg.DeepInspect,
+		InspectWorkers: cfg.InspectWorkers,
+		InspectRPS:     cfg.InspectRPS,
+		ObjectKeys:     objectKeys,
+		PeekArchives:   cfg.PeekArchives,
+	}
+
+	// Limit the scanning phase; mirroring below still uses ctx
+	scanCtx := ctx
+	if cfg.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(ctx, cfg.MaxDuration)
+		defer cancel()
+	}
+
+	// remaining returns the budget left after the waves so far (0 = unlimited)
+	var waves []waveResult
+	remaining := func() int64 {
+		if cfg.Budget <= 0 {
+			return 0
+		}
+		left := int64(cfg.Budget)
+		for _, w := range waves {
+			left -= w.stats.Scanned
+		}
+		return left
+	}
 
 	// Start scan
 	startTime := time.Now()
-	results := s.Scan(ctx, names)
+	maskLimit := int64(0)
+	if cfg.Budget > 0 {
+		maskLimit = max(int64(cfg.Budget-len(names)), 0)
+		if maskLimit == 0 {
+			mask = nil
+		}
+	}
+	wave := scanWave(scanCtx, scanCfg, reportWriter, streamNames(scanCtx, names, mask, maskLimit), total, rank.Origins(candidates))
+	waves = append(waves, wave)
 
-	// Start progress display with stats provider
-	go func() {
-		ticker := time.NewTicker(50 * time.Millisecond)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				stats := s.Stats()
-				progress.Update(stats.Scanned, stats.Found, stats.Public, stats.Private, stats.Errors, s.CurrentRPS())
-			}
+	// Feed discoveries back into the engine
+	if cfg.ExpandDepth > 0 {
+		seen := make(map[string]struct{}, len(names))
+		for _, name := range names {
+			seen[name] = struct{}{}
 		}
-	}()
-	progress.Start()
+		for depth := 1; depth <= cfg.ExpandDepth && scanCtx.Err() == nil; depth++ {
+			found := wave.found
+			if len(found) == 0 {
+				break
+			}
+			left := remaining()
+			if cfg.Budget > 0 && left <= 0 {
+				break
+			}
+			scorer.Observe(found...)
 
-	// Process results
-	for result := range results {
-		if err := multiWriter.WriteResult(result); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing result: %v\n", err)
+			var candidates []rank.Candidate
+			for _, name := range expandCandidates(scanCtx, engine, found, seen) {
+				candidates = append(candidates, rank.Candidate{Name: name, Source: rank.SourceExpansion})
+			}
+			if len(candidates) == 0 {
+				break
+			}
+			if cfg.Rank {
+				scorer.Rank(candidates)
+			}
+			if left > 0 && int64(len(candidates)) > left {
+				candidates = candidates[:left]
+			}
+			next := rank.Names(candidates)
+
+			fmt.Printf("\nExpansion wave %d: %d candidates from %d discovered bucket(s)\n\n", depth, len(next), len(found))
+			wave = scanWave(scanCtx, scanCfg, reportWriter, streamNames(scanCtx, next, nil, 0), int64(len(next)), nil)
+			waves = append(waves, wave)
 		}
 	}
 
-	// Stop progress display
-	progress.Stop()
-
 	// Print summary
-	stats := s.Stats()
+	var stats scanner.Stats
+	var publicBuckets []string
+	for _, w := range waves {
+		stats.Scanned += w.stats.Scanned
+		stats.Found += w.stats.Found
+		stats.Public += w.stats.Public
+		stats.Private += w.stats.Private
+		stats.Errors += w.stats.Errors
+		stats.NotFound += w.stats.NotFound
+		publicBuckets = append(publicBuckets, w.public...)
+	}
 	duration := time.Since(startTime).Round(time.Second)
+	if ctx.Err() == nil && scanCtx.Err() != nil {
+		fmt.Printf("\nStopped after reaching --max-duration %s\n", cfg.MaxDuration)
+	}
 
 	fmt.Printf("\n%s\n", "────────────────────────────────────────")
 	fmt.Printf("Scan completed in %s\n", duration)
 	fmt.Printf("Scanned: %d | Found: %d | Public: %d | Private: %d | Errors: %d | Not Found: %d\n",
 		stats.Scanned, stats.Found, stats.Public, stats.Private, stats.Errors, stats.NotFound)
+	if len(waves) > 1 {
+		for i, w := range waves {
+			fmt.Printf("  Wave %d: Scanned: %d | Found: %d | Public: %d | Private: %d | %s\n",
+				i, w.stats.Scanned, w.stats.Found, w.stats.Public, w.stats.Private, w.duration.Round(time.Second))
+		}
+	}
 	fmt.Printf("Results saved to: %s\n", cfg.OutputFile)
 
+	// Mirror public buckets
+	if cfg.Download && len(publicBuckets) > 0 {
+		fmt.Printf("\nMirroring %d public bucket(s) to %s\n", len(publicBuckets), cfg.DownloadDir)
+		for _, bucket := range publicBuckets {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := mirrorBucket(ctx, bucket); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: mirroring %s failed: %v\n", bucket, err)
+			}
+		}
+	}
+
 	return nil
 }
 
-func generateNames(ctx context.Context) ([]string, error) {
+// addDownloadFlags registers the mirroring options on cmd. Both the root
+// command (with --download) and the download command use them.
+func addDownloadFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cfg.DownloadDir, "download-dir", cfg.DownloadDir, "Directory that receives mirrored buckets")
+	cmd.Flags().StringSliceVar(&cfg.DownloadInclude, "download-include", nil, "Only mirror keys matching these globs")
+	cmd.Flags().StringSliceVar(&cfg.DownloadExclude, "download-exclude", nil, "Skip keys matching these globs")
+	cmd.Flags().Int64Var(&cfg.DownloadMaxObjectMB, "download-max-object-mb", cfg.DownloadMaxObjectMB, "Skip objects larger than this many MB (0 = unlimited)")
+	cmd.Flags().Int64Var(&cfg.DownloadMaxTotalMB, "download-max-total-mb", cfg.DownloadMaxTotalMB, "Skip objects that would take a bucket past this many MB in total (0 = unlimited)")
+	cmd.Flags().IntVar(&cfg.DownloadMaxObjects, "download-max-objects", cfg.DownloadMaxObjects, "Stop mirroring a bucket after this many objects (0 = unlimited)")
+	cmd.Flags().IntVar(&cfg.DownloadWorkers, "download-workers", cfg.DownloadWorkers, "Number of concurrent object downloads")
+}
+
+// mirrorBucket downloads a public bucket into its own directory under DownloadDir.
+func mirrorBucket(ctx context.Context, bucket string) error {
+	dir := filepath.Join(cfg.DownloadDir, bucket)
+
+	m, err := mirror.New(ctx, bucket, &mirror.Config{
+		OutputDir:     dir,
+		Include:       cfg.DownloadInclude,
+		Exclude:       cfg.DownloadExclude,
+		MaxObjectSize: cfg.DownloadMaxObjectMB << 20,
+		MaxTotalSize:  cfg.DownloadMaxTotalMB << 20,
+		MaxObjects:    cfg.DownloadMaxObjects,
+		Workers:       cfg.DownloadWorkers,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Mirroring s3://%s to %s...\n", bucket, dir)
+	manifest, err := m.Run(ctx)
+	if manifest != nil {
+		var failed int
+		for _, e := range manifest.Entries {
+			if e.Status == mirror.StatusFailed {
+				failed++
+			}
+		}
+		fmt.Printf("Mirrored %d/%d objects (%d bytes, %d skipped by filters, %d failed). Manifest: %s\n",
+			len(manifest.Entries)-failed, manifest.Listed, manifest.TotalBytes, manifest.Skipped, failed,
+			filepath.Join(dir, mirror.ManifestFile))
+	}
+	return err
+}
+
+// newMask compiles the --mask pattern with its custom charsets.
+func newMask() (*permutation.Mask, error) {
+	opts := permutation.MaskOptions{
+		Increment:    cfg.MaskIncrement,
+		IncrementMin: cfg.MaskIncrementMin,
+	}
+	for _, spec := range cfg.MaskCharsets {
+		n, chars, ok := strings.Cut(spec, "=")
+		if !ok || len(n) != 1 || n[0] < '1' || n[0] > '4' || chars == "" {
+			return nil, fmt.Errorf("invalid --mask-charset %q: expected N=chars with N from 1 to 4", spec)
+		}
+		opts.Custom[n[0]-'1'] = chars
+	}
+	return permutation.CompileMask(cfg.Mask, opts)
+}
+
+// streamNames feeds the generated names followed by the mask candidates, if
+// any, skipping mask names that were already generated.
+func streamNames(ctx context.Context, names []string, mask *permutation.Mask, maskLimit int64) <-chan string {
+	ch := make(chan string, 1000)
+	go func() {
+		defer close(ch)
+		seen := make(map[string]struct{}, len(names))
+		for _, name := range names {
+			seen[name] = struct{}{}
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- name:
+			}
+		}
+		if mask == nil {
+			return
+		}
+		var sent int64
+		mask.Each(func(name string) bool {
+			if _, ok := seen[name]; ok {
+				return true
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case ch <- name:
+				sent++
+				return maskLimit <= 0 || sent < maskLimit
+			}
+		})
+	}()
+	return ch
+}
+
+// newEngine builds the permutation engine with user templates and dictionaries.
+func newEngine() (*permutation.Engine, error) {
+	profile, err := permutation.LoadProfile(cfg.PermProfile)
+	if err != nil {
+		return nil, err
+	}
+	dates := profile.DateRange()
+	dates.Back = cfg.YearsBack
+	dates.Forward = cfg.YearsForward
+	if len(cfg.DateFormats) > 0 {
+		dates.Formats = cfg.DateFormats
+	}
+	engine, err := profile.EngineWithDates(dates)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile.Name, err)
+	}
+
+	for _, spec := range cfg.Dicts {
+		name, path, ok := strings.Cut(spec, "=")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid --dict %q: expected name=path", spec)
+		}
+		words, err := config.LoadWordlist(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dictionary %s: %w", name, err)
+		}
+		if engine.Dictionaries == nil {
+			engine.Dictionaries = make(map[string][]string)
+		}
+		engine.Dictionaries[name] = permutation.TransliterateAll(words)
+	}
+
+	for _, pattern := range cfg.Templates {
+		if err := engine.AddTemplate(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	if engine.Mutators, err = permutation.ParseMutators(cfg.Mutators); err != nil {
+		return nil, err
+	}
+	engine.MutateLimit = cfg.MutateLimit
+
+	return engine, nil
+}
+
+func generateNames(ctx context.Context, engine *permutation.Engine, scorer *rank.Scorer) ([]rank.Candidate, error) {
 	seen := make(map[string]struct{})
-	var allNames []string
+	var candidates []rank.Candidate
 	var contextWords []string
 
-	add := func(names []string) {
+	addFrom := func(source rank.Source, name, origin string) {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			candidates = append(candidates, rank.Candidate{Name: name, Source: source, Origin: origin})
+		}
+	}
+	add := func(source rank.Source, names []string) {
 		for _, name := range names {
-			if _, ok := seen[name]; !ok {
-				seen[name] = struct{}{}
-				allNames = append(allNames, name)
-			}
+			addFrom(source, name, "")
 		}
 	}
 
-	engine := permutation.Default()
+	if len(cfg.Seeds) > 0 || cfg.Domain != "" || cfg.SubdomainsFile != "" {
+		fmt.Printf("Permutation profile %s: up to ~%d names per seed\n", cfg.PermProfile, engine.Estimate())
+	}
 
-	// 1. CT Log subdomain discovery (if domain provided)
+	// 1. Subdomains from CT logs (-d) and recon tool output (--subdomains-file)
+	var subdomains []string
 	if cfg.Domain != "" {
+		sources, err := newReconSources()
+		if err != nil {
+			return nil, err
+		}
 		fmt.Printf("Fetching subdomains from CT logs for %s...\n", cfg.Domain)
-		ctClient := recon.NewCTClient(30*time.Second, cfg.CTLimit)
-		subdomains, err := ctClient.FetchSubdomains(ctx, cfg.Domain)
+		aggregator := recon.NewAggregator(time.Duration(cfg.ReconTimeout)*time.Second, cfg.CTLimit, sources...)
+		found, results, err := aggregator.FetchSubdomains(ctx, cfg.Domain)
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Printf("  %s: failed after %s: %v\n", r.Source, r.Duration.Round(time.Millisecond), r.Err)
+			} else {
+				fmt.Printf("  %s: %d subdomains in %s\n", r.Source, r.Count, r.Duration.Round(time.Millisecond))
+			}
+		}
 		if err != nil {
 			fmt.Printf("Warning: CT log fetch failed: %v\n", err)
 		} else {
-			add(subdomains)
-			// Extract words from subdomains and add them as seeds for permutations
-			wordMap := make(map[string]struct{})
-			for _, sub := range subdomains {
-				// Remove the base domain if present to focus on subparts
-				cleanSub := strings.TrimSuffix(sub, "."+cfg.Domain)
-				// Split by dots and dashes
-				parts := strings.FieldsFunc(cleanSub, func(r rune) bool {
-					return r == '.' || r == '-'
-				})
-				for _, part := range parts {
-					if len(part) > 2 { // Ignore very short parts like 'm', 'v1'
-						wordMap[part] = struct{}{}
-					}
-				}
+			subdomains = append(subdomains, found...)
+			fmt.Printf("CT logs processing completed\n")
+		}
+	}
+	if cfg.SubdomainsFile != "" {
+		hosts, err := recon.LoadSubdomains(cfg.SubdomainsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load subdomains file: %w", err)
+		}
+		subdomains = append(subdomains, hosts...)
+		fmt.Printf("Subdomains file loaded %d hosts\n", len(hosts))
+	}
+
+	if len(subdomains) > 0 && cfg.CNAMESweep {
+		fmt.Printf("Resolving CNAMEs of %d subdomains...\n", len(subdomains))
+		records := recon.SweepCNAMEs(ctx, dns.NewResolver(), subdomains, 20)
+		var buckets []string
+		for _, r := range records {
+			if r.Bucket != "" {
+				buckets = append(buckets, r.Bucket)
+				addFrom(rank.SourceCNAME, r.Bucket, r.Host)
+				fmt.Printf("  %s -> %s (bucket %s)\n", r.Host, r.Chain[0], r.Bucket)
 			}
+		}
+		scorer.Observe(buckets...)
+		fmt.Printf("CNAME sweep: %d of %d subdomains are aliases, %d point at S3\n", len(records), len(subdomains), len(buckets))
+	}
 
-			if len(wordMap) > 0 {
-				fmt.Printf("Extracted %d unique words from CT logs for deeper scanning\n", len(wordMap))
-				for word := range wordMap {
-					contextWords = append(contextWords, word)
-					// Add permutations of each extracted word
-					add(engine.Generate(word))
+	if len(subdomains) > 0 {
+		add(rank.SourceCT, subdomains)
+		add(rank.SourceSeed, recon.SubdomainsToSeeds(subdomains, cfg.Domain))
+
+		// Permute the most frequent words of the subdomains. They also feed
+		// the scorer, as hostnames would count stopwords like www and mail.
+		words := recon.ExtractWords(subdomains, cfg.Domain, recon.WordOptions{MaxWords: cfg.CTWords})
+		if len(words) > 0 {
+			list := make([]string, len(words))
+			for i, w := range words {
+				list[i] = fmt.Sprintf("%s (%d)", w.Word, w.Count)
+			}
+			fmt.Printf("Extracted %d words from subdomains for deeper scanning: %s\n", len(words), strings.Join(list, ", "))
+			for _, w := range words {
+				contextWords = append(contextWords, w.Word)
+				for range w.Count {
+					scorer.Observe(w.Word)
 				}
+				add(rank.SourceSeed, engine.Generate(w.Word))
 			}
-			fmt.Printf("CT logs processing completed\n")
+		}
+	}
+
+	// 1b. Bucket references in local artifacts (As-Is)
+	if len(cfg.Extract) > 0 {
+		buckets, err := extractBuckets(cfg.Extract)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract bucket names: %w", err)
+		}
+		add(rank.SourceExtract, buckets)
+		scorer.Observe(buckets...)
+		fmt.Printf("Extracted %d bucket names from local artifacts\n", len(buckets))
+	}
+
+	// 1c. Bucket references on the target website (As-Is)
+	if cfg.Crawl != "" {
+		fmt.Printf("Crawling %s (depth %d, up to %d pages)...\n", cfg.Crawl, cfg.CrawlDepth, cfg.CrawlPages)
+		crawler := recon.NewCrawler(&recon.CrawlerConfig{
+			Timeout:       time.Duration(cfg.Timeout) * time.Second,
+			MaxDepth:      cfg.CrawlDepth,
+			MaxPages:      cfg.CrawlPages,
+			RespectRobots: cfg.CrawlRobots,
+		})
+		crawlCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.ReconTimeout)*time.Second)
+		refs, err := crawler.Buckets(crawlCtx, cfg.Crawl)
+		cancel()
+		if err != nil {
+			fmt.Printf("Warning: crawl failed: %v\n", err)
+		} else {
+			buckets := uniqueBuckets(refs)
+			add(rank.SourceExtract, buckets)
+			scorer.Observe(buckets...)
+			fmt.Printf("Crawl found %d bucket names\n", len(buckets))
+		}
+	}
+
+	// 1d. Archived URLs of the target domain
+	if cfg.Wayback {
+		fmt.Printf("Fetching archived URLs for %s...\n", cfg.Domain)
+		wayback := recon.NewWayback(&recon.WaybackConfig{
+			Timeout:  time.Duration(cfg.ReconTimeout) * time.Second,
+			BaseURL:  cfg.WaybackURL,
+			RPS:      cfg.WaybackRPS,
+			MaxPages: cfg.WaybackPages,
+		})
+		urls, err := wayback.URLs(ctx, cfg.Domain)
+		if err != nil {
+			fmt.Printf("Warning: archive lookup failed: %v\n", err)
+		} else {
+			buckets := uniqueBuckets(recon.URLReferences(urls))
+			add(rank.SourceExtract, buckets)
+			scorer.Observe(buckets...)
+
+			words := recon.PathWords(urls, recon.WordOptions{MaxWords: cfg.CTWords})
+			for _, w := range words {
+				contextWords = append(contextWords, w.Word)
+				add(rank.SourceSeed, engine.Generate(w.Word))
+			}
+			fmt.Printf("Archive returned %d URLs: %d bucket names, %d path words\n", len(urls), len(buckets), len(words))
+		}
+	}
+
+	// 1e. Bucket names in public code
+	if cfg.CodeSearch != "" {
+		search, err := newCodeSearch()
+		if err != nil {
+			return nil, err
+		}
+		targets := cfg.Seeds
+		if cfg.Domain != "" {
+			targets = []string{cfg.Domain}
+		}
+		for _, target := range targets {
+			refs, err := search.Buckets(ctx, target)
+			if err != nil {
+				fmt.Printf("Warning: %s code search for %s failed: %v\n", search.Name(), target, err)
+				continue
+			}
+			buckets := uniqueBuckets(refs)
+			add(rank.SourceExtract, buckets)
+			scorer.Observe(buckets...)
+			fmt.Printf("Code search for %s found %d bucket names\n", target, len(buckets))
 		}
 	}
 
 	// 2. Permutation engine on seed
-	if cfg.Seed != "" {
-		permNames := engine.Generate(cfg.Seed)
-		add(permNames)
-		fmt.Printf("Permutation engine generated %d names from seed: %s\n", len(permNames), cfg.Seed)
+	for _, seed := range cfg.Seeds {
+		permNames := engine.Generate(seed)
+		add(rank.SourceSeed, permNames)
+		fmt.Printf("Permutation engine generated %d names from seed: %s\n", len(permNames), seed)
+	}
+
+	// 2b. Seed combinations
+	if cfg.CombineSeeds && len(cfg.Seeds) > 1 {
+		comboNames := engine.Combine(cfg.Seeds, cfg.ComboLimit)
+		add(rank.SourceCombo, comboNames)
+		fmt.Printf("Seed combinations generated %d names from %d seeds\n", len(comboNames), len(cfg.Seeds))
+	}
+
+	// 2c. Offline name model
+	if scorer.Model != nil {
+		for _, seed := range cfg.Seeds {
+			modelNames := scorer.Model.Generate(seed, cfg.ModelCount)
+			add(rank.SourceModel, modelNames)
+			fmt.Printf("Name model generated %d names from seed: %s\n", len(modelNames), seed)
+		}
 	}
 
 	// 3. Wordlist (Raw)
@@ -275,42 +804,120 @@ func generateNames(ctx context.Context) ([]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to load wordlist: %w", err)
 		}
-		add(words)
+		words = permutation.TransliterateAll(words)
+		add(rank.SourceWordlist, words)
+		scorer.Observe(words...)
 		fmt.Printf("Wordlist loaded %d names\n", len(words))
 	}
 
 	// 4. AI generation
 	if cfg.AIEnabled {
-		if cfg.Seed == "" && len(contextWords) == 0 {
+		var seed string
+		if len(cfg.Seeds) > 0 {
+			// Primary seed leads; other identifiers become context
+			seed = cfg.Seeds[0]
+			contextWords = append(contextWords, cfg.Seeds[1:]...)
+		}
+		if seed == "" && len(contextWords) == 0 {
 			fmt.Println("Warning: AI generation requires a seed keyword or discovered context. Skipping AI generation.")
 		} else {
 			fmt.Printf("Generating AI suggestions using %s (with context-aware discovery)...\n", cfg.AIProvider)
 
-			aiCfg := &ai.Config{
-				Provider:    cfg.AIProvider,
-				Model:       cfg.AIModel,
-				APIKey:      cfg.AIKey,
-				BaseURL:     cfg.AIBaseURL,
-				Temperature: 0.7,
-			}
-
-			generator, err := ai.NewGenerator(aiCfg)
+			generator, err := newAIGenerator()
 			if err != nil {
 				fmt.Printf("Warning: AI generation failed: %v\n", err)
 			} else {
 				// Use both seed and discovered context words
-				aiNames, err := generator.Generate(ctx, cfg.Seed, contextWords, cfg.AICount)
+				aiNames, err := generator.Generate(ctx, seed, contextWords, cfg.AICount)
 				if err != nil {
 					fmt.Printf("Warning: AI generation failed: %v\n", err)
 				} else {
-					add(aiNames)
+					add(rank.SourceAI, aiNames)
 					fmt.Printf("AI (%s) discovered patterns and generated %d names\n", generator.Name(), len(aiNames))
 				}
 			}
 		}
 	}
 
-	return allNames, nil
+	return candidates, nil
+}
+
+// newCodeSearch builds the --code-search source. Without --code-search-token
+// the token falls back to GITHUB_TOKEN or GITLAB_TOKEN.
+func newCodeSearch() (*recon.CodeSearch, error) {
+	if cfg.CodeSearchToken == "" {
+		switch strings.ToLower(cfg.CodeSearch) {
+		case "github":
+			cfg.CodeSearchToken = os.Getenv("GITHUB_TOKEN")
+		case "gitlab":
+			cfg.CodeSearchToken = os.Getenv("GITLAB_TOKEN")
+		}
+	}
+	return recon.NewCodeSearch(&recon.CodeSearchConfig{
+		Timeout:  time.Duration(cfg.ReconTimeout) * time.Second,
+		Provider: cfg.CodeSearch,
+		BaseURL:  cfg.CodeSearchURL,
+		Token:    cfg.CodeSearchToken,
+		MaxPages: cfg.CodeSearchPages,
+	})
+}
+
+// newReconSources builds the subdomain sources selected by --recon-sources.
+// Censys is skipped when no credentials are configured.
+func newReconSources() ([]recon.Source, error) {
+	timeout := time.Duration(cfg.ReconTimeout) * time.Second
+	if cfg.CertSpotterKey == "" {
+		cfg.CertSpotterKey = os.Getenv("CERTSPOTTER_API_KEY")
+	}
+	if cfg.CensysID == "" {
+		cfg.CensysID = os.Getenv("CENSYS_API_ID")
+	}
+	if cfg.CensysSecret == "" {
+		cfg.CensysSecret = os.Getenv("CENSYS_API_SECRET")
+	}
+
+	var sources []recon.Source
+	for _, name := range cfg.ReconSources {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "crtsh", "crt.sh":
+			cacheDir := cfg.CTCacheDir
+			if cacheDir == "" {
+				if dir, err := os.UserCacheDir(); err == nil {
+					cacheDir = filepath.Join(dir, "s3finder")
+				}
+			}
+			sources = append(sources, recon.NewCTClientWithConfig(&recon.CTConfig{
+				Timeout:        timeout,
+				MaxResults:     cfg.CTLimit,
+				Retries:        3,
+				Backoff:        2 * time.Second,
+				ExcludeExpired: cfg.CTExcludeExpired,
+				CacheDir:       cacheDir,
+				CacheTTL:       cfg.CTCacheTTL,
+			}))
+		case "certspotter":
+			sources = append(sources, recon.NewCertSpotter(timeout, cfg.CertSpotterKey))
+		case "censys":
+			if cfg.CensysID != "" && cfg.CensysSecret != "" {
+				sources = append(sources, recon.NewCensys(timeout, cfg.CensysID, cfg.CensysSecret, cfg.CensysURL))
+			}
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown recon source %q (valid: crtsh, certspotter, censys)", name)
+		}
+	}
+	return sources, nil
+}
+
+// newAIGenerator creates the AI generator from the configuration.
+func newAIGenerator() (ai.Generator, error) {
+	return ai.NewGenerator(&ai.Config{
+		Provider:    cfg.AIProvider,
+		Model:       cfg.AIModel,
+		APIKey:      cfg.AIKey,
+		BaseURL:     cfg.AIBaseURL,
+		Temperature: 0.7,
+	})
 }
 
 func printBanner() {
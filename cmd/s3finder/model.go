@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xeloxa/s3finder/internal/config"
+	"github.com/xeloxa/s3finder/pkg/model"
+)
+
+// newModelCmd returns the "model" command group.
+func newModelCmd() *cobra.Command {
+	modelCmd := &cobra.Command{
+		Use:   "model",
+		Short: "Train and inspect the offline bucket name model",
+	}
+
+	var (
+		out       string
+		order     int
+		vocabFile string
+	)
+	trainCmd := &cobra.Command{
+		Use:   "train <report.json|report.txt|names.txt>...",
+		Short: "Train an n-gram model from past reports and name lists",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var names []string
+			for _, path := range args {
+				corpus, err := model.LoadCorpus(path)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", path, err)
+				}
+				names = append(names, corpus...)
+			}
+
+			vocabulary := model.DefaultVocabulary()
+			if vocabFile != "" {
+				words, err := config.LoadWordlist(vocabFile)
+				if err != nil {
+					return fmt.Errorf("failed to load vocabulary: %w", err)
+				}
+				vocabulary = append(vocabulary, words...)
+			}
+
+			m := model.Train(names, model.TrainOptions{Order: order, Vocabulary: vocabulary})
+			if m.Names == 0 {
+				return fmt.Errorf("no usable bucket names in %d input name(s)", len(names))
+			}
+			if err := m.Save(out); err != nil {
+				return fmt.Errorf("failed to write model: %w", err)
+			}
+
+			fmt.Printf("Trained order-%d model on %d names (%d contexts). Saved to %s\n",
+				m.Order, m.Names, len(m.Transitions), out)
+			return nil
+		},
+	}
+	trainCmd.Flags().StringVarP(&out, "output", "o", "s3finder-model.json", "Model file to write")
+	trainCmd.Flags().IntVar(&order, "order", 3, "n-gram order (tokens of context + 1)")
+	trainCmd.Flags().StringVar(&vocabFile, "vocab", "", "Extra naming words to keep literally, one per line")
+
+	var count int
+	generateCmd := &cobra.Command{
+		Use:   "generate <model> <seed>",
+		Short: "Print the most likely names for a seed",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := model.Load(args[0])
+			if err != nil {
+				return err
+			}
+			for _, name := range m.Generate(args[1], count) {
+				fmt.Printf("%.3f\t%s\n", m.Score(name, args[1]), name)
+			}
+			return nil
+		},
+	}
+	generateCmd.Flags().IntVarP(&count, "count", "n", 20, "Number of names")
+
+	modelCmd.AddCommand(trainCmd, generateCmd)
+	return modelCmd
+}
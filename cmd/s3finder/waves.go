@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/xeloxa/s3finder/pkg/output"
+	"github.com/xeloxa/s3finder/pkg/permutation"
+	"github.com/xeloxa/s3finder/pkg/scanner"
+)
+
+// waveResult summarizes one scan wave.
+type waveResult struct {
+	stats    scanner.Stats
+	found    []string // Existing buckets, public or private
+	public   []string
+	duration time.Duration
+}
+
+// scanWave scans one batch of names with its own scanner and progress bar,
+// writing results to the shared report. origins maps names to where they
+// were found and may be nil.
+func scanWave(ctx context.Context, scanCfg *scanner.Config, report output.Writer, names <-chan string, total int64, origins map[string]string) waveResult {
+	start := time.Now()
+
+	progress := output.NewProgress(&output.ProgressConfig{
+		Output:      os.Stderr,
+		Total:       total,
+		RefreshRate: 100 * time.Millisecond,
+		ShowRPS:     true,
+		UseColors:   !cfg.NoColor,
+		BarWidth:    25,
+		ExternalMu:  &outputMu,
+		ShowInspect: cfg.DeepInspect,
+	})
+
+	// Setup output writers (pass progress for coordinated output)
+	realtimeWriter := output.NewRealtime(&output.RealtimeConfig{
+		Output:    os.Stdout,
+		UseColors: !cfg.NoColor,
+		UseLinks:  !cfg.NoColor, // Enable clickable links when colors are enabled
+		Verbose:   cfg.Verbose,
+		Progress:  progress, // Coordinate output with progress bar
+	})
+	multiWriter := output.NewMultiWriter(realtimeWriter, report)
+
+	s := scanner.New(scanCfg)
+	results := s.ScanStream(ctx, names, total)
+
+	// Start progress display with stats provider
+	waveCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-waveCtx.Done():
+				return
+			case <-ticker.C:
+				stats := s.Stats()
+				progress.Update(stats.Scanned, stats.Found, stats.Public, stats.Private, stats.Errors, s.CurrentRPS())
+				progress.SetInspectQueue(stats.InspectQueue)
+			}
+		}
+	}()
+	progress.Start()
+
+	// Process results
+	var wave waveResult
+	for result := range results {
+		result.Origin = origins[result.Bucket]
+		if err := multiWriter.WriteResult(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing result: %v\n", err)
+		}
+		switch result.Probe {
+		case scanner.BucketExists:
+			wave.public = append(wave.public, result.Bucket)
+			wave.found = append(wave.found, result.Bucket)
+		case scanner.BucketForbidden:
+			wave.found = append(wave.found, result.Bucket)
+		}
+	}
+
+	// Stop progress display
+	stop()
+	progress.Stop()
+
+	wave.stats = s.Stats()
+	wave.duration = time.Since(start)
+	return wave
+}
+
+// expandCandidates builds the next wave from discovered buckets: dictionary
+// siblings and permutations from the engine, plus AI suggestions when
+// enabled. Names in seen are skipped and the new ones are added to it.
+func expandCandidates(ctx context.Context, engine *permutation.Engine, found []string, seen map[string]struct{}) []string {
+	var candidates []string
+	add := func(names []string) {
+		for _, name := range names {
+			if _, ok := seen[name]; !ok && permutation.IsValidBucketName(name) {
+				seen[name] = struct{}{}
+				candidates = append(candidates, name)
+			}
+		}
+	}
+
+	for _, name := range found {
+		seen[name] = struct{}{}
+	}
+	add(engine.ExpandFound(found, cfg.ExpandLimit))
+
+	if cfg.ExpandAI && cfg.AIEnabled {
+		var tokens []string
+		for _, name := range found {
+			tokens = append(tokens, permutation.Tokenize(name)...)
+		}
+
+		seed := found[0]
+		if len(cfg.Seeds) > 0 {
+			seed = cfg.Seeds[0]
+		}
+
+		generator, err := newAIGenerator()
+		if err == nil {
+			var aiNames []string
+			if aiNames, err = generator.Generate(ctx, seed, append(tokens, found...), cfg.AICount); err == nil {
+				add(aiNames)
+			}
+		}
+		if err != nil {
+			fmt.Printf("Warning: AI expansion failed: %v\n", err)
+		}
+	}
+
+	return candidates
+}
@@ -3,21 +3,88 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // Config holds all application configuration.
 type Config struct {
 	// Scanner settings
-	Workers     int     `mapstructure:"workers"`
-	MaxRPS      float64 `mapstructure:"max_rps"`
-	Timeout     int     `mapstructure:"timeout"` // seconds
-	DeepInspect bool    `mapstructure:"deep_inspect"`
+	Workers        int     `mapstructure:"workers"`
+	MaxRPS         float64 `mapstructure:"max_rps"`
+	Timeout        int     `mapstructure:"timeout"` // seconds
+	DeepInspect    bool    `mapstructure:"deep_inspect"`
+	InspectWorkers int     `mapstructure:"inspect_workers"`
+	InspectRPS     float64 `mapstructure:"inspect_rps"`
+	ProbeKeys      bool    `mapstructure:"probe_keys"`
+	KeysFile       string  `mapstructure:"keys_file"`
+	PeekArchives   bool    `mapstructure:"peek_archives"`
 
 	// Input settings
-	Seed     string `mapstructure:"seed"`
-	Wordlist string `mapstructure:"wordlist"`
-	Domain   string `mapstructure:"domain"`
-	CTLimit  int    `mapstructure:"ct_limit"`
+	Seeds        []string `mapstructure:"seeds"`
+	SeedsFile    string   `mapstructure:"seeds_file"`
+	CombineSeeds bool     `mapstructure:"combine_seeds"` // Join seeds with each other (acme-rocket)
+	ComboLimit   int      `mapstructure:"combo_limit"`   // Max names from seed combinations
+	Wordlist     string   `mapstructure:"wordlist"`
+	Domain       string   `mapstructure:"domain"`
+	CTLimit      int      `mapstructure:"ct_limit"`
+	CTWords      int      `mapstructure:"ct_words"` // Subdomain words permuted, most frequent first
+
+	SubdomainsFile string   `mapstructure:"subdomains_file"` // amass, subfinder, massdns or plain output
+	Extract        []string `mapstructure:"extract"`         // Paths to pull bucket references from
+
+	// Recon settings
+	ReconSources   []string `mapstructure:"recon_sources"`   // Subdomain sources: crtsh, certspotter, censys
+	ReconTimeout   int      `mapstructure:"recon_timeout"`   // Per-source timeout in seconds
+	CertSpotterKey string   `mapstructure:"certspotter_key"` // Optional, raises the rate limit
+	CensysID       string   `mapstructure:"censys_id"`
+	CensysSecret   string   `mapstructure:"censys_secret"`
+	CensysURL      string   `mapstructure:"censys_url"`  // Censys-compatible API base URL
+	CNAMESweep     bool     `mapstructure:"cname_sweep"` // Resolve subdomain CNAMEs for S3 targets
+
+	// crt.sh settings
+	CTExcludeExpired bool          `mapstructure:"ct_exclude_expired"` // Skip expired certificates
+	CTCacheDir       string        `mapstructure:"ct_cache_dir"`       // "" = user cache directory
+	CTCacheTTL       time.Duration `mapstructure:"ct_cache_ttl"`       // 0 disables the cache
+
+	// Web archive settings
+	Wayback      bool    `mapstructure:"wayback"`       // Search archived URLs of Domain
+	WaybackURL   string  `mapstructure:"wayback_url"`   // CDX server base URL
+	WaybackPages int     `mapstructure:"wayback_pages"` // Max CDX pages
+	WaybackRPS   float64 `mapstructure:"wayback_rps"`   // Max CDX requests per second
+
+	// Code search settings
+	CodeSearch      string `mapstructure:"code_search"`       // Provider: github, gitlab ("" = off)
+	CodeSearchURL   string `mapstructure:"code_search_url"`   // API base URL
+	CodeSearchToken string `mapstructure:"code_search_token"` // Required by both providers
+	CodeSearchPages int    `mapstructure:"code_search_pages"` // Pages per query
+
+	// Crawler settings
+	Crawl       string `mapstructure:"crawl"`        // Website to crawl for bucket references
+	CrawlDepth  int    `mapstructure:"crawl_depth"`  // Links followed from the start page
+	CrawlPages  int    `mapstructure:"crawl_pages"`  // Max pages fetched
+	CrawlRobots bool   `mapstructure:"crawl_robots"` // Honor robots.txt
+
+	// Permutation settings
+	PermProfile      string        `mapstructure:"perm_profile"` // Built-in profile name or YAML/TXT path
+	YearsBack        int           `mapstructure:"years_back"`
+	YearsForward     int           `mapstructure:"years_forward"`
+	DateFormats      []string      `mapstructure:"date_formats"`       // Overrides the profile's date formats
+	Mask             string        `mapstructure:"mask"`               // Hashcat-style mask, e.g. acme-?l?l?d
+	MaskCharsets     []string      `mapstructure:"mask_charsets"`      // Custom charsets as 1=?l?d
+	MaskIncrement    bool          `mapstructure:"mask_increment"`     // Also try shorter prefixes of the mask
+	MaskIncrementMin int           `mapstructure:"mask_increment_min"` // Shortest prefix length with MaskIncrement
+	ExpandDepth      int           `mapstructure:"expand_depth"`       // Waves fed back from found buckets (0 = off)
+	ExpandLimit      int           `mapstructure:"expand_limit"`       // Max candidates per wave
+	ExpandAI         bool          `mapstructure:"expand_ai"`          // Also ask the AI provider during expansion
+	Model            string        `mapstructure:"model"`              // n-gram model file from "model train"
+	ModelCount       int           `mapstructure:"model_count"`        // Model names per seed
+	Templates        []string      `mapstructure:"templates"`          // Naming templates, e.g. {env}{sep}{seed}
+	Dicts            []string      `mapstructure:"dictionaries"`       // Template dictionaries as name=path
+	Mutators         []string      `mapstructure:"mutators"`           // Seed mutators: abbrev, plural, vowels, leet, typo
+	MutateLimit      int           `mapstructure:"mutate_limit"`       // Max mutated names per seed (0 = unlimited)
+	Rank             bool          `mapstructure:"rank"`               // Scan the most likely names first
+	Budget           int           `mapstructure:"budget"`             // Maximum names to scan (0 = unlimited)
+	MaxDuration      time.Duration `mapstructure:"max_duration"`       // Stop scanning after this long (0 = unlimited)
 
 	// AI settings
 	AIEnabled  bool   `mapstructure:"ai_enabled"`
@@ -27,6 +94,16 @@ type Config struct {
 	AIBaseURL  string `mapstructure:"ai_base_url"`
 	AICount    int    `mapstructure:"ai_count"`
 
+	// Download settings
+	Download            bool     `mapstructure:"download"`
+	DownloadDir         string   `mapstructure:"download_dir"`
+	DownloadInclude     []string `mapstructure:"download_include"`
+	DownloadExclude     []string `mapstructure:"download_exclude"`
+	DownloadMaxObjectMB int64    `mapstructure:"download_max_object_mb"`
+	DownloadMaxTotalMB  int64    `mapstructure:"download_max_total_mb"`
+	DownloadMaxObjects  int      `mapstructure:"download_max_objects"`
+	DownloadWorkers     int      `mapstructure:"download_workers"`
+
 	// Output settings
 	OutputFile   string `mapstructure:"output_file"`
 	OutputFormat string `mapstructure:"output_format"`
@@ -37,20 +114,48 @@ type Config struct {
 // Default returns the default configuration.
 func Default() *Config {
 	return &Config{
-		Workers:      50,
-		MaxRPS:       150,
-		Timeout:      15,
-		DeepInspect:  true,
-		Wordlist:     "",
-		CTLimit:      100,
-		AIEnabled:    false,
-		AIProvider:   "openai",
-		AIModel:      "gpt-4o-mini",
-		AICount:      50,
-		OutputFile:   "results.json",
-		OutputFormat: "json",
-		NoColor:      false,
-		Verbose:      false,
+		Workers:          50,
+		MaxRPS:           150,
+		Timeout:          15,
+		DeepInspect:      true,
+		InspectWorkers:   10,
+		InspectRPS:       20,
+		ProbeKeys:        false,
+		PeekArchives:     false,
+		CombineSeeds:     false,
+		ComboLimit:       5000,
+		Wordlist:         "",
+		CTLimit:          100,
+		CTWords:          30,
+		ReconSources:     []string{"crtsh", "certspotter", "censys"},
+		ReconTimeout:     30,
+		CNAMESweep:       false,
+		CTCacheTTL:       24 * time.Hour,
+		WaybackPages:     10,
+		WaybackRPS:       1,
+		CodeSearchPages:  1,
+		CrawlDepth:       2,
+		CrawlPages:       100,
+		PermProfile:      "default",
+		YearsBack:        3,
+		YearsForward:     1,
+		MaskIncrementMin: 1,
+		ExpandDepth:      0,
+		ExpandLimit:      5000,
+		ModelCount:       200,
+		MutateLimit:      200,
+		Rank:             true,
+		AIEnabled:        false,
+		AIProvider:       "openai",
+		AIModel:          "gpt-4o-mini",
+		AICount:          50,
+		Download:         false,
+		DownloadDir:      "downloads",
+		DownloadWorkers:  4,
+		OutputFile:       "results.json",
+		OutputFormat:     "json",
+		NoColor:          false,
+		Verbose:          false,
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDefault(t *testing.T) {
@@ -18,12 +19,54 @@ func TestDefault(t *testing.T) {
 		{"MaxRPS", cfg.MaxRPS, 150.0},
 		{"Timeout", cfg.Timeout, 15},
 		{"DeepInspect", cfg.DeepInspect, true},
+		{"InspectWorkers", cfg.InspectWorkers, 10},
+		{"InspectRPS", cfg.InspectRPS, 20.0},
+		{"ProbeKeys", cfg.ProbeKeys, false},
+		{"KeysFile", cfg.KeysFile, ""},
+		{"PeekArchives", cfg.PeekArchives, false},
+		{"SeedsFile", cfg.SeedsFile, ""},
+		{"CombineSeeds", cfg.CombineSeeds, false},
+		{"ComboLimit", cfg.ComboLimit, 5000},
 		{"Wordlist", cfg.Wordlist, ""},
 		{"CTLimit", cfg.CTLimit, 100},
+		{"SubdomainsFile", cfg.SubdomainsFile, ""},
+		{"CTWords", cfg.CTWords, 30},
+		{"ReconTimeout", cfg.ReconTimeout, 30},
+		{"CNAMESweep", cfg.CNAMESweep, false},
+		{"CensysURL", cfg.CensysURL, ""},
+		{"CTExcludeExpired", cfg.CTExcludeExpired, false},
+		{"CTCacheDir", cfg.CTCacheDir, ""},
+		{"CTCacheTTL", cfg.CTCacheTTL, 24 * time.Hour},
+		{"Wayback", cfg.Wayback, false},
+		{"WaybackPages", cfg.WaybackPages, 10},
+		{"WaybackRPS", cfg.WaybackRPS, 1.0},
+		{"CodeSearch", cfg.CodeSearch, ""},
+		{"CodeSearchPages", cfg.CodeSearchPages, 1},
+		{"CrawlDepth", cfg.CrawlDepth, 2},
+		{"CrawlPages", cfg.CrawlPages, 100},
+		{"CrawlRobots", cfg.CrawlRobots, false},
+		{"PermProfile", cfg.PermProfile, "default"},
+		{"YearsBack", cfg.YearsBack, 3},
+		{"YearsForward", cfg.YearsForward, 1},
+		{"Mask", cfg.Mask, ""},
+		{"MaskIncrement", cfg.MaskIncrement, false},
+		{"MaskIncrementMin", cfg.MaskIncrementMin, 1},
+		{"ExpandDepth", cfg.ExpandDepth, 0},
+		{"ExpandLimit", cfg.ExpandLimit, 5000},
+		{"ExpandAI", cfg.ExpandAI, false},
+		{"Model", cfg.Model, ""},
+		{"ModelCount", cfg.ModelCount, 200},
+		{"MutateLimit", cfg.MutateLimit, 200},
+		{"Rank", cfg.Rank, true},
+		{"Budget", cfg.Budget, 0},
+		{"MaxDuration", cfg.MaxDuration, time.Duration(0)},
 		{"AIEnabled", cfg.AIEnabled, false},
 		{"AIProvider", cfg.AIProvider, "openai"},
 		{"AIModel", cfg.AIModel, "gpt-4o-mini"},
 		{"AICount", cfg.AICount, 50},
+		{"Download", cfg.Download, false},
+		{"DownloadDir", cfg.DownloadDir, "downloads"},
+		{"DownloadWorkers", cfg.DownloadWorkers, 4},
 		{"OutputFile", cfg.OutputFile, "results.json"},
 		{"OutputFormat", cfg.OutputFormat, "json"},
 		{"NoColor", cfg.NoColor, false},
@@ -0,0 +1,210 @@
+package dns
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// maxCNAMEHops bounds CNAME chains, which may loop.
+const maxCNAMEHops = 8
+
+// CNAMEChain returns the CNAME targets of host in resolution order, e.g.
+// assets.acme.com -> acme-assets.s3.amazonaws.com -> s3-1-w.amazonaws.com.
+// Unlike net.Resolver.LookupCNAME, which only reports the final canonical
+// name, every hop is kept. A host without a CNAME returns an empty chain.
+func (r *Resolver) CNAMEChain(ctx context.Context, host string) ([]string, error) {
+	var chain []string
+	name := strings.TrimSuffix(strings.ToLower(host), ".")
+
+	for hop := 0; hop < maxCNAMEHops; hop++ {
+		target, err := r.queryCNAME(ctx, name)
+		if err != nil {
+			return chain, err
+		}
+		if target == "" {
+			break
+		}
+		chain = append(chain, target)
+		name = target
+	}
+	return chain, nil
+}
+
+// queryCNAME asks one server for the CNAME record of name, trying another
+// on failure. It returns "" when name has no CNAME or does not exist.
+func (r *Resolver) queryCNAME(ctx context.Context, name string) (string, error) {
+	q, err := newQuestion(name)
+	if err != nil {
+		return "", err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		server := r.servers[rand.Intn(len(r.servers))]
+		target, err := exchangeCNAME(ctx, server, q)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return target, nil
+	}
+	return "", fmt.Errorf("cname lookup failed for %s: %w", name, lastErr)
+}
+
+// newQuestion builds the CNAME question for name.
+func newQuestion(name string) (dnsmessage.Question, error) {
+	n, err := dnsmessage.NewName(name + ".")
+	if err != nil {
+		return dnsmessage.Question{}, fmt.Errorf("invalid hostname %q: %w", name, err)
+	}
+	return dnsmessage.Question{Name: n, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET}, nil
+}
+
+// exchangeCNAME sends the question over UDP and repeats it over TCP when
+// the answer comes back truncated.
+func exchangeCNAME(ctx context.Context, server string, q dnsmessage.Question) (string, error) {
+	for _, network := range []string{"udp", "tcp"} {
+		id, err := randomID()
+		if err != nil {
+			return "", err
+		}
+		query, err := (&dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+			Questions: []dnsmessage.Question{q},
+		}).Pack()
+		if err != nil {
+			return "", err
+		}
+
+		resp, err := exchange(ctx, network, server, query)
+		if err != nil {
+			return "", err
+		}
+		target, truncated, err := parseCNAME(resp, id, q)
+		if err != nil || !truncated {
+			return target, err
+		}
+	}
+	return "", errors.New("truncated dns response over tcp")
+}
+
+// randomID returns an unpredictable query ID.
+func randomID() (uint16, error) {
+	var b [2]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b[:]), nil
+}
+
+// exchange sends a query over UDP or TCP and returns the response.
+func exchange(ctx context.Context, network, server string, query []byte) ([]byte, error) {
+	d := net.Dialer{Timeout: 2 * time.Second}
+	conn, err := d.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if network == "udp" {
+		if _, err := conn.Write(query); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 1232)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+
+	// TCP messages carry a two-byte length prefix
+	if _, err := conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(query)))); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	var size [2]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, binary.BigEndian.Uint16(size[:]))
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+var errMismatch = errors.New("dns response does not match the query")
+
+// parseCNAME returns the target of the first CNAME record for q in the
+// answer section of resp, and whether resp was truncated.
+func parseCNAME(resp []byte, id uint16, q dnsmessage.Question) (string, bool, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(resp)
+	if err != nil {
+		return "", false, err
+	}
+	if h.ID != id || !h.Response {
+		return "", false, errMismatch
+	}
+	if h.Truncated {
+		return "", true, nil
+	}
+	switch h.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("dns server returned %s", h.RCode)
+	}
+
+	got, err := p.Question()
+	if err != nil {
+		return "", false, err
+	}
+	if got.Type != q.Type || !strings.EqualFold(got.Name.String(), q.Name.String()) {
+		return "", false, errMismatch
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return "", false, err
+	}
+
+	for {
+		ah, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if ah.Type != dnsmessage.TypeCNAME || !strings.EqualFold(ah.Name.String(), q.Name.String()) {
+			if err := p.SkipAnswer(); err != nil {
+				return "", false, err
+			}
+			continue
+		}
+		rr, err := p.CNAMEResource()
+		if err != nil {
+			return "", false, err
+		}
+		return strings.ToLower(strings.TrimSuffix(rr.CNAME.String(), ".")), false, nil
+	}
+}
@@ -0,0 +1,163 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// fakeDNS answers CNAME queries from records over UDP and TCP on the same
+// port. Names missing from records get NXDOMAIN, names mapped to "" an
+// empty answer. Names in truncated are answered with the TC bit over UDP,
+// so only TCP returns their record.
+func fakeDNS(t *testing.T, records map[string]string, truncated []string) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	ln, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	answer := func(req []byte, udp bool) []byte {
+		var msg dnsmessage.Message
+		if err := msg.Unpack(req); err != nil || len(msg.Questions) != 1 {
+			return nil
+		}
+		q := msg.Questions[0]
+		name := strings.TrimSuffix(q.Name.String(), ".")
+
+		msg.Header.Response = true
+		target, ok := records[name]
+		switch {
+		case udp && slices.Contains(truncated, name):
+			msg.Header.Truncated = true
+		case !ok:
+			msg.Header.RCode = dnsmessage.RCodeNameError
+		case target != "":
+			msg.Answers = []dnsmessage.Resource{{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: 300},
+				Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(target + ".")},
+			}}
+		}
+		resp, _ := msg.Pack()
+		return resp
+	}
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := answer(buf[:n], true); resp != nil {
+				pc.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			var size [2]byte
+			if _, err := io.ReadFull(conn, size[:]); err == nil {
+				req := make([]byte, binary.BigEndian.Uint16(size[:]))
+				if _, err := io.ReadFull(conn, req); err == nil {
+					resp := answer(req, false)
+					conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(resp))), resp...))
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	return pc.LocalAddr().String()
+}
+
+func TestResolver_CNAMEChain(t *testing.T) {
+	server := fakeDNS(t, map[string]string{
+		"assets.acme.com":              "acme-assets.s3.amazonaws.com",
+		"acme-assets.s3.amazonaws.com": "s3-1-w.amazonaws.com",
+		"s3-1-w.amazonaws.com":         "",
+		"www.acme.com":                 "",
+		"big.acme.com":                 "acme-big.s3.amazonaws.com",
+		"acme-big.s3.amazonaws.com":    "",
+		"loop-a.acme.com":              "loop-b.acme.com",
+		"loop-b.acme.com":              "loop-a.acme.com",
+	}, []string{"big.acme.com"})
+	r := &Resolver{servers: []string{server}}
+
+	tests := []struct {
+		host     string
+		expected []string
+	}{
+		{"assets.acme.com", []string{"acme-assets.s3.amazonaws.com", "s3-1-w.amazonaws.com"}},
+		{"ASSETS.acme.com.", []string{"acme-assets.s3.amazonaws.com", "s3-1-w.amazonaws.com"}},
+		{"big.acme.com", []string{"acme-big.s3.amazonaws.com"}}, // Retried over TCP
+		{"www.acme.com", nil},
+		{"missing.acme.com", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := r.CNAMEChain(context.Background(), tt.host)
+		if err != nil {
+			t.Errorf("CNAMEChain(%q) error = %v", tt.host, err)
+			continue
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("CNAMEChain(%q) = %v, want %v", tt.host, got, tt.expected)
+		}
+	}
+
+	if got, _ := r.CNAMEChain(context.Background(), "loop-a.acme.com"); len(got) != maxCNAMEHops {
+		t.Errorf("CNAMEChain(loop) returned %d hops, want %d", len(got), maxCNAMEHops)
+	}
+}
+
+func TestParseCNAME_Mismatch(t *testing.T) {
+	q, err := newQuestion("acme.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := newQuestion("evil.com")
+
+	pack := func(id uint16, response bool, question dnsmessage.Question) []byte {
+		msg, err := (&dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: id, Response: response},
+			Questions: []dnsmessage.Question{question},
+		}).Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+
+	tests := []struct {
+		name string
+		resp []byte
+	}{
+		{"short message", pack(7, true, q)[:5]},
+		{"mismatched id", pack(8, true, q)},
+		{"not a response", pack(7, false, q)},
+		{"other question", pack(7, true, other)},
+	}
+	for _, tt := range tests {
+		if _, _, err := parseCNAME(tt.resp, 7, q); err == nil {
+			t.Errorf("%s: parseCNAME() expected error", tt.name)
+		}
+	}
+}
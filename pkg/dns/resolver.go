@@ -17,10 +17,12 @@ var providers = []string{
 
 type Resolver struct {
 	internal *net.Resolver
+	servers  []string // Queried directly by CNAMEChain
 }
 
 func NewResolver() *Resolver {
 	return &Resolver{
+		servers: providers,
 		internal: &net.Resolver{
 			PreferGo: true,
 			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
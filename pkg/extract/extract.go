@@ -0,0 +1,84 @@
+// Package extract finds S3 bucket references in text and local artifacts.
+package extract
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/xeloxa/s3finder/pkg/permutation"
+)
+
+// bucketChars matches a bucket name as it may appear inside a URL.
+const bucketChars = `([a-z0-9][a-z0-9.-]{1,61}[a-z0-9])`
+
+// s3Host matches the S3 endpoint part of a hostname: s3, s3-us-west-2,
+// s3.dualstack.eu-west-1, s3-website-us-east-1 and China regions.
+const s3Host = `s3(?:[.-][a-z0-9-]+)*\.amazonaws\.com(?:\.cn)?`
+
+// patterns extract bucket names. Each has a single capture group.
+var patterns = []*regexp.Regexp{
+	// Virtual-hosted style: acme-assets.s3.us-east-1.amazonaws.com
+	regexp.MustCompile(bucketChars + `\.` + s3Host),
+	// Path style: s3.amazonaws.com/acme-assets, s3-eu-west-1.amazonaws.com/acme
+	regexp.MustCompile(`(?:^|[^a-z0-9.-])` + s3Host + `/` + bucketChars),
+	// s3://acme-assets/key
+	regexp.MustCompile(`s3a?n?://` + bucketChars),
+	// arn:aws:s3:::acme-assets/*
+	regexp.MustCompile(`arn:aws[a-z-]*:s3:::` + bucketChars),
+	// Terraform state and HCL: "bucket": "acme", bucket = "acme"
+	regexp.MustCompile(`"?\bbucket"?\s*[:=]\s*"` + bucketChars + `"`),
+	// CloudFormation and serverless: BucketName: acme, "BucketName": "acme"
+	regexp.MustCompile(`"?\bbucket_?name"?\s*:\s*["']?` + bucketChars + `["']?(?:\s|,|}|$)`),
+}
+
+// Find returns the bucket names referenced in s, in order of appearance
+// and without duplicates.
+func Find(s string) []string {
+	s = strings.ToLower(s)
+
+	type match struct {
+		pos  int
+		name string
+	}
+	var matches []match
+	for _, re := range patterns {
+		for _, m := range re.FindAllStringSubmatchIndex(s, -1) {
+			name := s[m[2]:m[3]]
+			if isBucket(name) {
+				matches = append(matches, match{m[2], name})
+			}
+		}
+	}
+
+	// Order by position so results follow the text
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].pos < matches[j-1].pos; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+
+	seen := make(map[string]struct{})
+	var names []string
+	for _, m := range matches {
+		if _, ok := seen[m.name]; !ok {
+			seen[m.name] = struct{}{}
+			names = append(names, m.name)
+		}
+	}
+	return names
+}
+
+// isBucket rejects names that match the patterns but cannot be buckets, such
+// as S3 endpoints themselves or IP addresses.
+func isBucket(name string) bool {
+	if !permutation.IsValidBucketName(name) {
+		return false
+	}
+	if strings.Contains(name, "..") || strings.HasPrefix(name, "s3.") || strings.HasPrefix(name, "s3-") || name == "s3" {
+		return false
+	}
+	if strings.Trim(name, "0123456789.") == "" {
+		return false
+	}
+	return true
+}
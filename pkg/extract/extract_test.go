@@ -0,0 +1,46 @@
+package extract
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"virtual hosted", `<img src="https://acme-assets.s3.amazonaws.com/logo.png">`, []string{"acme-assets"}},
+		{"regional virtual hosted", `fetch("https://acme-media.s3.eu-west-1.amazonaws.com/a.mp4")`, []string{"acme-media"}},
+		{"legacy regional", `acme-old.s3-us-west-2.amazonaws.com`, []string{"acme-old"}},
+		{"website endpoint", `http://acme-site.s3-website-us-east-1.amazonaws.com/`, []string{"acme-site"}},
+		{"dotted bucket", `https://static.acme.com.s3.amazonaws.com/app.js`, []string{"static.acme.com"}},
+		{"path style", `https://s3.amazonaws.com/acme-backups/db.sql`, []string{"acme-backups"}},
+		{"regional path style", `https://s3.eu-central-1.amazonaws.com/acme-eu/file`, []string{"acme-eu"}},
+		{"dualstack path style", `s3.dualstack.us-east-1.amazonaws.com/acme-ds/x`, []string{"acme-ds"}},
+		{"china", `acme-cn.s3.cn-north-1.amazonaws.com.cn`, []string{"acme-cn"}},
+		{"s3 uri", `aws s3 cp s3://acme-logs/2024/ .`, []string{"acme-logs"}},
+		{"s3a uri", `spark.read.parquet("s3a://acme-lake/events")`, []string{"acme-lake"}},
+		{"arn", `"Resource": "arn:aws:s3:::acme-data/*"`, []string{"acme-data"}},
+		{"gov arn", `arn:aws-us-gov:s3:::acme-gov`, []string{"acme-gov"}},
+		{"tfstate", `"bucket": "acme-terraform-state",`, []string{"acme-terraform-state"}},
+		{"hcl", `  bucket = "acme-tf"`, []string{"acme-tf"}},
+		{"cloudformation yaml", `      BucketName: acme-cfn-logs`, []string{"acme-cfn-logs"}},
+		{"cloudformation json", `"BucketName": "acme-cfn"}`, []string{"acme-cfn"}},
+		{"uppercase url", `HTTPS://ACME-UP.S3.AMAZONAWS.COM/`, []string{"acme-up"}},
+		{"several", `s3://acme-a and arn:aws:s3:::acme-b and s3://acme-a`, []string{"acme-a", "acme-b"}},
+		{"bare endpoint", `https://s3.amazonaws.com/`, nil},
+		{"template variable", `bucket = "${var.name}"`, nil},
+		{"cfn intrinsic", `BucketName: !Sub ${AWS::StackName}-logs`, nil},
+		{"unrelated", `https://example.com/s3/acme`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Find(tt.input); !slices.Equal(got, tt.expected) {
+				t.Errorf("Find(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package extract
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Reference is a bucket name found in a file.
+type Reference struct {
+	Bucket string `json:"bucket"`
+	// File is the path of the file. Archive members are written as
+	// app.apk!/assets/config.json and base64 HAR or Burp bodies as
+	// capture.har#3 (the index of the encoded body).
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"` // 1-based, 0 for binary content
+}
+
+// Location returns file:line, or just the file when the line is unknown.
+func (r Reference) Location() string {
+	if r.Line == 0 {
+		return r.File
+	}
+	return r.File + ":" + strconv.Itoa(r.Line)
+}
+
+// Options configures Paths.
+type Options struct {
+	MaxFileSize int64 // Larger files are skipped (0 = 100 MB)
+}
+
+// defaultMaxFileSize applies when Options.MaxFileSize is not set.
+const defaultMaxFileSize = 100 << 20
+
+// maxArchiveDepth bounds recursion into archives inside archives.
+const maxArchiveDepth = 2
+
+// skipDirs are never descended into.
+var skipDirs = map[string]struct{}{".git": {}, ".hg": {}, ".svn": {}}
+
+// archiveExts are ZIP-based formats whose members are scanned.
+var archiveExts = map[string]struct{}{
+	".apk": {}, ".aab": {}, ".xapk": {}, ".ipa": {}, ".jar": {}, ".war": {}, ".zip": {},
+}
+
+// Paths scans files and directory trees and calls fn for every bucket
+// reference. Source files, JS bundles, HAR files, Burp XML exports,
+// APK/IPA packages, Terraform state and CloudFormation templates are
+// understood; other binary files are searched for printable strings.
+// Files and directories below a root that cannot be read are skipped; only
+// a root that cannot be accessed is an error.
+func Paths(paths []string, opts Options, fn func(Reference)) error {
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = defaultMaxFileSize
+	}
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root {
+					return err
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if _, skip := skipDirs[d.Name()]; skip && path != root {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil || info.Size() > opts.MaxFileSize {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			scanData(path, data, 0, opts, fn)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Data scans content that did not come from the local filesystem, such as
+// a downloaded page, and calls fn for every bucket reference. name is
+// reported as the file and selects the parser like a file extension would.
+func Data(name string, data []byte, fn func(Reference)) {
+	scanData(name, data, 0, Options{MaxFileSize: defaultMaxFileSize}, fn)
+}
+
+// scanData dispatches on the kind of content.
+func scanData(name string, data []byte, depth int, opts Options, fn func(Reference)) {
+	ext := strings.ToLower(filepath.Ext(name))
+
+	if _, ok := archiveExts[ext]; ok && depth < maxArchiveDepth {
+		if scanArchive(name, data, depth, opts, fn) {
+			return
+		}
+	}
+
+	if isBinary(data) {
+		scanStrings(name, data, fn)
+		return
+	}
+
+	scanLines(name, data, fn)
+
+	switch {
+	case ext == ".har":
+		scanHAR(name, data, fn)
+	case ext == ".xml" && bytes.Contains(data[:min(len(data), 4096)], []byte("burpVersion")):
+		scanBurp(name, data, fn)
+	}
+}
+
+// scanLines reports references line by line.
+func scanLines(name string, data []byte, fn func(Reference)) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), len(data)+1)
+	for line := 1; sc.Scan(); line++ {
+		for _, bucket := range Find(sc.Text()) {
+			fn(Reference{Bucket: bucket, File: name, Line: line})
+		}
+	}
+}
+
+// scanArchive scans the members of a ZIP-based package. It returns false if
+// data is not a readable archive.
+func scanArchive(name string, data []byte, depth int, opts Options, fn func(Reference)) bool {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || int64(f.UncompressedSize64) > opts.MaxFileSize {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			continue
+		}
+		member, err := io.ReadAll(io.LimitReader(rc, opts.MaxFileSize))
+		rc.Close()
+		if err != nil {
+			continue
+		}
+		scanData(name+"!/"+f.Name, member, depth+1, opts, fn)
+	}
+	return true
+}
+
+// minStringLen is the shortest printable run kept from binary files.
+const minStringLen = 6
+
+// scanStrings reports references in the printable runs of binary content,
+// like strings(1). NUL bytes are dropped first so UTF-16 text (Android
+// resources, Windows binaries) is found as well.
+func scanStrings(name string, data []byte, fn func(Reference)) {
+	seen := make(map[string]struct{})
+	report := func(run []byte) {
+		if len(run) < minStringLen {
+			return
+		}
+		for _, bucket := range Find(string(run)) {
+			if _, ok := seen[bucket]; !ok {
+				seen[bucket] = struct{}{}
+				fn(Reference{Bucket: bucket, File: name})
+			}
+		}
+	}
+
+	var run []byte
+	for _, b := range data {
+		switch {
+		case b == 0:
+			continue
+		case b >= 0x20 && b < 0x7f:
+			run = append(run, b)
+		default:
+			report(run)
+			run = run[:0]
+		}
+	}
+	report(run)
+}
+
+// isBinary reports whether data looks like binary content.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// harFile is the part of a HAR archive that can hide references.
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Response struct {
+				Content struct {
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// scanHAR decodes base64 response bodies, which the line scan cannot see
+// into. Plain bodies and URLs are already covered by scanLines.
+func scanHAR(name string, data []byte, fn func(Reference)) {
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return
+	}
+	for i, e := range har.Log.Entries {
+		if e.Response.Content.Encoding != "base64" {
+			continue
+		}
+		body, err := base64.StdEncoding.DecodeString(e.Response.Content.Text)
+		if err != nil {
+			continue
+		}
+		scanData(fmt.Sprintf("%s#%d", name, i), body, maxArchiveDepth, Options{}, fn)
+	}
+}
+
+// burpItem matches base64-encoded requests and responses in a Burp export.
+var burpItem = regexp.MustCompile(`<(?:request|response) base64="true"><!\[CDATA\[([A-Za-z0-9+/=\s]*)\]\]>`)
+
+// scanBurp decodes the base64 requests and responses of a Burp XML export.
+// Plain ones are covered by scanLines.
+func scanBurp(name string, data []byte, fn func(Reference)) {
+	for i, m := range burpItem.FindAllSubmatch(data, -1) {
+		body, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(m[1]), nil)))
+		if err != nil {
+			continue
+		}
+		scanData(fmt.Sprintf("%s#%d", name, i), body, maxArchiveDepth, Options{}, fn)
+	}
+}
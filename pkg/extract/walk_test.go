@@ -0,0 +1,154 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func collect(t *testing.T, paths ...string) []string {
+	t.Helper()
+	var locations []string
+	err := Paths(paths, Options{}, func(r Reference) {
+		locations = append(locations, r.Bucket+" "+r.Location())
+	})
+	if err != nil {
+		t.Fatalf("Paths() error = %v", err)
+	}
+	return locations
+}
+
+func TestPaths_Text(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "src", "app.js"), []byte("const a = 1;\nconst cdn = 'https://acme-cdn.s3.amazonaws.com/';\n"))
+	writeFile(t, filepath.Join(dir, "terraform.tfstate"), []byte("{\n  \"resources\": [{\n    \"bucket\": \"acme-state\"\n  }]\n}\n"))
+	writeFile(t, filepath.Join(dir, "template.yaml"), []byte("Resources:\n  Logs:\n    Properties:\n      BucketName: acme-cfn-logs\n"))
+	writeFile(t, filepath.Join(dir, ".git", "config"), []byte("s3://acme-ignored\n"))
+
+	got := collect(t, dir)
+	want := []string{
+		"acme-cdn " + filepath.Join(dir, "src", "app.js") + ":2",
+		"acme-cfn-logs " + filepath.Join(dir, "template.yaml") + ":4",
+		"acme-state " + filepath.Join(dir, "terraform.tfstate") + ":3",
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestPaths_Archive(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("assets/config.json")
+	w.Write([]byte("{\n\"upload\": \"s3://acme-mobile-uploads\"\n}"))
+	w, _ = zw.Create("classes.dex")
+	w.Write([]byte("dex\x00\x01\x02https://acme-dex.s3.amazonaws.com/x\x00\xff"))
+	zw.Close()
+
+	path := filepath.Join(t.TempDir(), "app.apk")
+	writeFile(t, path, buf.Bytes())
+
+	got := collect(t, path)
+	want := []string{
+		"acme-mobile-uploads " + path + "!/assets/config.json:2",
+		"acme-dex " + path + "!/classes.dex",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestPaths_UTF16(t *testing.T) {
+	var data []byte
+	data = append(data, 0xff, 0xfe)
+	for _, c := range []byte("arn:aws:s3:::acme-resources") {
+		data = append(data, c, 0)
+	}
+
+	path := filepath.Join(t.TempDir(), "resources.arsc")
+	writeFile(t, path, data)
+
+	if got := collect(t, path); !slices.Equal(got, []string{"acme-resources " + path}) {
+		t.Errorf("Paths() = %v", got)
+	}
+}
+
+func TestPaths_HAR(t *testing.T) {
+	body := base64.StdEncoding.EncodeToString([]byte("var x = 1;\nload('s3://acme-har-hidden/a');"))
+	har := `{"log": {"entries": [
+  {"request": {"url": "https://acme-har.s3.amazonaws.com/app.js"}, "response": {"content": {"text": "ok"}}},
+  {"request": {"url": "https://example.com/"}, "response": {"content": {"text": "` + body + `", "encoding": "base64"}}}
+]}}`
+	path := filepath.Join(t.TempDir(), "capture.har")
+	writeFile(t, path, []byte(har))
+
+	got := collect(t, path)
+	want := []string{
+		"acme-har " + path + ":2",
+		"acme-har-hidden " + path + "#1:2",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestPaths_Burp(t *testing.T) {
+	resp := base64.StdEncoding.EncodeToString([]byte("HTTP/1.1 200 OK\r\n\r\n<script src=\"//acme-burp.s3.amazonaws.com/a.js\"></script>"))
+	xml := `<?xml version="1.0"?>
+<items burpVersion="2023.1">
+  <item>
+    <url><![CDATA[https://app.example.com/]]></url>
+    <request base64="true"><![CDATA[R0VUIC8gSFRUUC8xLjE=]]></request>
+    <response base64="true"><![CDATA[` + resp + `]]></response>
+  </item>
+</items>`
+	path := filepath.Join(t.TempDir(), "burp.xml")
+	writeFile(t, path, []byte(xml))
+
+	if got := collect(t, path); !slices.Equal(got, []string{"acme-burp " + path + "#1:3"}) {
+		t.Errorf("Paths() = %v", got)
+	}
+}
+
+func TestPaths_Missing(t *testing.T) {
+	if err := Paths([]string{"/nonexistent/dir"}, Options{}, func(Reference) {}); err == nil {
+		t.Error("Paths() error = nil, want error for missing path")
+	}
+}
+
+func TestPaths_Unreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a", "secret.env"), []byte("s3://acme-hidden\n"))
+	writeFile(t, filepath.Join(dir, "b.env"), []byte("s3://acme-locked\n"))
+	writeFile(t, filepath.Join(dir, "c.env"), []byte("s3://acme-visible\n"))
+	for _, p := range []string{filepath.Join(dir, "a"), filepath.Join(dir, "b.env")} {
+		if err := os.Chmod(p, 0); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Chmod(p, 0755) })
+	}
+
+	got := collect(t, dir)
+	want := []string{"acme-visible " + filepath.Join(dir, "c.env") + ":1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
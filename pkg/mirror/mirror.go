@@ -0,0 +1,499 @@
+package mirror
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/xeloxa/s3finder/pkg/permutation"
+)
+
+// ManifestFile is the name of the chain-of-custody manifest written to the
+// root of every mirror directory.
+const ManifestFile = "manifest.json"
+
+// partSuffix marks partially downloaded files that can be resumed.
+const partSuffix = ".part"
+
+// Entry status values recorded in the manifest.
+const (
+	StatusDownloaded = "downloaded"
+	StatusResumed    = "resumed"
+	StatusUnchanged  = "unchanged"
+	StatusFailed     = "failed"
+)
+
+// Config holds configuration for mirroring a bucket.
+type Config struct {
+	OutputDir     string   // Local directory that receives the objects
+	Include       []string // Glob patterns; when set, only matching keys are mirrored
+	Exclude       []string // Glob patterns for keys to skip
+	MaxObjectSize int64    // Skip objects larger than this many bytes (0 = unlimited)
+	MaxTotalSize  int64    // Skip objects that would exceed this many bytes in total (0 = unlimited)
+	MaxObjects    int      // Stop selecting objects after this many keys (0 = unlimited)
+	Workers       int      // Concurrent object downloads
+	Region        string   // Bucket region; looked up when empty
+	Endpoint      string   // Optional S3 endpoint override (path-style)
+}
+
+// DefaultConfig returns sensible defaults for mirroring.
+func DefaultConfig() *Config {
+	return &Config{
+		OutputDir: "downloads",
+		Workers:   4,
+	}
+}
+
+// Entry describes a single mirrored object.
+type Entry struct {
+	Key          string    `json:"key"`
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+	MD5          string    `json:"md5,omitempty"`
+	SHA256       string    `json:"sha256,omitempty"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// Manifest records what was mirrored from a bucket and when.
+type Manifest struct {
+	Bucket      string    `json:"bucket"`
+	Region      string    `json:"region"`
+	Source      string    `json:"source"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Listed      int       `json:"listed"`
+	Skipped     int       `json:"skipped"`
+	TotalBytes  int64     `json:"total_bytes"`
+	Entries     []Entry   `json:"entries"`
+}
+
+// Mirror anonymously copies objects from a public bucket to disk.
+type Mirror struct {
+	bucket string
+	cfg    Config
+	client *s3.Client
+}
+
+// New creates a Mirror for the given bucket.
+func New(ctx context.Context, bucket string, cfg *Config) (*Mirror, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	// The name becomes a local directory; reject paths like ../x
+	if !permutation.IsValidBucketName(bucket) {
+		return nil, fmt.Errorf("invalid bucket name %q", bucket)
+	}
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	c := *cfg
+	if c.OutputDir == "" {
+		c.OutputDir = DefaultConfig().OutputDir
+	}
+	if c.Workers <= 0 {
+		c.Workers = DefaultConfig().Workers
+	}
+	if c.Region == "" {
+		c.Region = lookupRegion(ctx, bucket)
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(c.Region),
+		config.WithCredentialsProvider(aws.AnonymousCredentials{}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if c.Endpoint != "" {
+			o.BaseEndpoint = aws.String(c.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &Mirror{bucket: bucket, cfg: c, client: client}, nil
+}
+
+// Run lists the bucket, downloads every selected object and writes the manifest.
+// Objects already present with a matching ETag are left untouched, and
+// partial downloads from an earlier run are resumed.
+func (m *Mirror) Run(ctx context.Context) (*Manifest, error) {
+	if err := os.MkdirAll(m.cfg.OutputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	manifest := &Manifest{
+		Bucket:    m.bucket,
+		Region:    m.cfg.Region,
+		Source:    fmt.Sprintf("s3://%s", m.bucket),
+		StartedAt: time.Now(),
+	}
+	previous := m.loadPreviousManifest()
+
+	objects, listed, skipped, err := m.listObjects(ctx)
+	manifest.Listed = listed
+	manifest.Skipped = skipped
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bucket: %w", err)
+	}
+
+	jobs := make(chan object)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < m.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range jobs {
+				entry := m.fetch(ctx, obj, previous[obj.key])
+				mu.Lock()
+				manifest.Entries = append(manifest.Entries, entry)
+				if entry.Status != StatusFailed {
+					manifest.TotalBytes += entry.Size
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, obj := range objects {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- obj:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(manifest.Entries, func(i, j int) bool {
+		return manifest.Entries[i].Key < manifest.Entries[j].Key
+	})
+	manifest.CompletedAt = time.Now()
+
+	if err := m.writeManifest(manifest); err != nil {
+		return manifest, err
+	}
+
+	return manifest, ctx.Err()
+}
+
+// object is a listed key selected for download.
+type object struct {
+	key          string
+	size         int64
+	etag         string
+	lastModified time.Time
+}
+
+// listObjects pages through the bucket and applies filters and caps.
+func (m *Mirror) listObjects(ctx context.Context) ([]object, int, int, error) {
+	var selected []object
+	var listed, skipped int
+	var total int64
+
+	paginator := s3.NewListObjectsV2Paginator(m.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(m.bucket),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return selected, listed, skipped, err
+		}
+
+		for _, o := range page.Contents {
+			if o.Key == nil || strings.HasSuffix(*o.Key, "/") {
+				continue
+			}
+			listed++
+
+			obj := object{
+				key:  *o.Key,
+				size: aws.ToInt64(o.Size),
+				etag: strings.Trim(aws.ToString(o.ETag), `"`),
+			}
+			if o.LastModified != nil {
+				obj.lastModified = *o.LastModified
+			}
+
+			if !m.selected(obj) {
+				skipped++
+				continue
+			}
+			if m.cfg.MaxObjects > 0 && len(selected) >= m.cfg.MaxObjects {
+				return selected, listed, skipped, nil
+			}
+			if m.cfg.MaxTotalSize > 0 && total+obj.size > m.cfg.MaxTotalSize {
+				// Smaller objects later in the listing may still fit
+				skipped++
+				continue
+			}
+
+			total += obj.size
+			selected = append(selected, obj)
+		}
+	}
+
+	return selected, listed, skipped, nil
+}
+
+// selected applies include/exclude globs and the per-object size cap.
+func (m *Mirror) selected(obj object) bool {
+	if m.cfg.MaxObjectSize > 0 && obj.size > m.cfg.MaxObjectSize {
+		return false
+	}
+	if len(m.cfg.Include) > 0 && !MatchAny(m.cfg.Include, obj.key) {
+		return false
+	}
+	return !MatchAny(m.cfg.Exclude, obj.key)
+}
+
+// MatchAny reports whether the key, or its base name, matches any glob pattern.
+func MatchAny(patterns []string, key string) bool {
+	base := path.Base(key)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch downloads a single object, resuming a partial file when possible.
+func (m *Mirror) fetch(ctx context.Context, obj object, prev *Entry) Entry {
+	entry := Entry{
+		Key:          obj.key,
+		Size:         obj.size,
+		ETag:         obj.etag,
+		LastModified: obj.lastModified,
+		DownloadedAt: time.Now(),
+	}
+
+	dest, err := m.localPath(obj.key)
+	if err != nil {
+		entry.Status = StatusFailed
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Path, _ = filepath.Rel(m.cfg.OutputDir, dest)
+
+	// Already mirrored with the same content
+	if prev != nil && prev.ETag == obj.etag && prev.SHA256 != "" {
+		if info, err := os.Stat(dest); err == nil && info.Size() == obj.size {
+			entry.Status = StatusUnchanged
+			entry.MD5 = prev.MD5
+			entry.SHA256 = prev.SHA256
+			entry.DownloadedAt = prev.DownloadedAt
+			return entry
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		entry.Status = StatusFailed
+		entry.Error = err.Error()
+		return entry
+	}
+
+	status, err := m.download(ctx, obj, dest)
+	if err != nil {
+		entry.Status = StatusFailed
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Status = status
+
+	entry.MD5, entry.SHA256, err = hashFile(dest)
+	if err != nil {
+		entry.Status = StatusFailed
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// download streams an object into dest via a .part file.
+func (m *Mirror) download(ctx context.Context, obj object, dest string) (string, error) {
+	part := dest + partSuffix
+	status := StatusDownloaded
+
+	var offset int64
+	if info, err := os.Stat(part); err == nil && info.Size() < obj.size {
+		offset = info.Size()
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(m.bucket),
+		Key:    aws.String(obj.key),
+	}
+	if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+		if obj.etag != "" {
+			// Never splice bytes from a different version of the object
+			input.IfMatch = aws.String(`"` + obj.etag + `"`)
+		}
+	}
+
+	out, err := m.client.GetObject(ctx, input)
+	if err != nil && offset > 0 {
+		// The object changed or ranges are unsupported; start over
+		offset = 0
+		input.Range = nil
+		input.IfMatch = nil
+		out, err = m.client.GetObject(ctx, input)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer out.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		status = StatusResumed
+	}
+
+	f, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(f, out.Body); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(part, dest); err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+// localPath maps an object key to a path inside the output directory.
+func (m *Mirror) localPath(key string) (string, error) {
+	root, err := filepath.Abs(m.cfg.OutputDir)
+	if err != nil {
+		return "", err
+	}
+
+	dest := filepath.Join(root, filepath.FromSlash(key))
+	if dest == root || !strings.HasPrefix(dest, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("key %q escapes output directory", key)
+	}
+	if filepath.Base(dest) == ManifestFile && filepath.Dir(dest) == root {
+		return "", fmt.Errorf("key %q collides with manifest", key)
+	}
+
+	return dest, nil
+}
+
+// loadPreviousManifest returns entries from an earlier run, keyed by object key.
+func (m *Mirror) loadPreviousManifest() map[string]*Entry {
+	entries := make(map[string]*Entry)
+
+	data, err := os.ReadFile(filepath.Join(m.cfg.OutputDir, ManifestFile))
+	if err != nil {
+		return entries
+	}
+
+	var prev Manifest
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return entries
+	}
+
+	for i := range prev.Entries {
+		e := &prev.Entries[i]
+		if e.Status != StatusFailed {
+			entries[e.Key] = e
+		}
+	}
+
+	return entries
+}
+
+// writeManifest stores the manifest next to the mirrored objects.
+func (m *Mirror) writeManifest(manifest *Manifest) error {
+	f, err := os.Create(filepath.Join(m.cfg.OutputDir, ManifestFile))
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// hashFile returns the hex MD5 and SHA-256 digests of a file.
+func hashFile(p string) (string, string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	md5Hash := md5.New()
+	shaHash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(md5Hash, shaHash), f); err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(shaHash.Sum(nil)), nil
+}
+
+// lookupRegion reads the bucket region from the x-amz-bucket-region header.
+func lookupRegion(ctx context.Context, bucket string) string {
+	url := fmt.Sprintf("https://%s.s3.amazonaws.com", bucket)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "us-east-1"
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "us-east-1"
+	}
+	defer resp.Body.Close()
+
+	if region := resp.Header.Get("x-amz-bucket-region"); region != "" {
+		return region
+	}
+
+	return "us-east-1"
+}
@@ -0,0 +1,313 @@
+package mirror
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeBucket serves a path-style subset of the S3 API for a single bucket.
+type fakeBucket struct {
+	name        string
+	objects     map[string]string
+	rangeHits   atomic.Int64
+	objectFetch atomic.Int64
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := "/" + f.name
+	if r.URL.Path == prefix || r.URL.Path == prefix+"/" {
+		f.list(w)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, prefix+"/")
+	body, ok := f.objects[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<Error><Code>NoSuchKey</Code></Error>`)
+		return
+	}
+	f.objectFetch.Add(1)
+
+	w.Header().Set("ETag", `"`+etagFor(body)+`"`)
+	if rng := r.Header.Get("Range"); rng != "" {
+		f.rangeHits.Add(1)
+		start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(body)-1, len(body)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)-start))
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, body[start:])
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	fmt.Fprint(w, body)
+}
+
+func (f *fakeBucket) list(w http.ResponseWriter) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><ListBucketResult>`)
+	fmt.Fprintf(&b, "<Name>%s</Name><IsTruncated>false</IsTruncated>", f.name)
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		body := f.objects[key]
+		fmt.Fprintf(&b, `<Contents><Key>%s</Key><Size>%d</Size><ETag>"%s"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></Contents>`,
+			key, len(body), etagFor(body))
+	}
+	b.WriteString(`</ListBucketResult>`)
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprint(w, b.String())
+}
+
+func etagFor(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:8])
+}
+
+func newTestMirror(t *testing.T, fake *fakeBucket, cfg *Config) (*Mirror, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	cfg.Endpoint = server.URL
+	cfg.Region = "us-east-1"
+	m, err := New(context.Background(), fake.name, cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return m, server
+}
+
+func TestNew_RequiresBucket(t *testing.T) {
+	if _, err := New(context.Background(), "", nil); err == nil {
+		t.Error("New() with empty bucket should return error")
+	}
+}
+
+func TestNew_InvalidBucket(t *testing.T) {
+	for _, bucket := range []string{"../../tmp/x", "acme/backups", "Acme_Backups"} {
+		if _, err := New(context.Background(), bucket, &Config{OutputDir: t.TempDir()}); err == nil {
+			t.Errorf("New(%q) should return error", bucket)
+		}
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		key      string
+		expected bool
+	}{
+		{"base name match", []string{"*.sql"}, "dumps/db.sql", true},
+		{"full key match", []string{"dumps/*"}, "dumps/db.sql", true},
+		{"no match", []string{"*.zip"}, "dumps/db.sql", false},
+		{"empty patterns", nil, "dumps/db.sql", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchAny(tt.patterns, tt.key); got != tt.expected {
+				t.Errorf("MatchAny(%v, %q) = %v, want %v", tt.patterns, tt.key, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMirror_Run(t *testing.T) {
+	fake := &fakeBucket{
+		name: "acme-public",
+		objects: map[string]string{
+			"index.html":      "<html>hello</html>",
+			"dumps/db.sql":    "CREATE TABLE users;",
+			"images/logo.png": "PNGDATA",
+			"big/archive.zip": strings.Repeat("z", 500),
+			"../escape.txt":   "nope",
+		},
+	}
+	dir := t.TempDir()
+	m, _ := newTestMirror(t, fake, &Config{
+		OutputDir:     dir,
+		Exclude:       []string{"*.png"},
+		MaxObjectSize: 100,
+		Workers:       2,
+	})
+
+	manifest, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if manifest.Listed != 5 {
+		t.Errorf("Listed = %d, want 5", manifest.Listed)
+	}
+	if manifest.Skipped != 2 {
+		t.Errorf("Skipped = %d, want 2 (png excluded, zip too large)", manifest.Skipped)
+	}
+
+	statuses := make(map[string]Entry)
+	for _, e := range manifest.Entries {
+		statuses[e.Key] = e
+	}
+
+	sql := statuses["dumps/db.sql"]
+	if sql.Status != StatusDownloaded {
+		t.Errorf("db.sql status = %q, want %q", sql.Status, StatusDownloaded)
+	}
+	sum := sha256.Sum256([]byte("CREATE TABLE users;"))
+	if sql.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("db.sql sha256 = %q, want %q", sql.SHA256, hex.EncodeToString(sum[:]))
+	}
+	if sql.ETag != etagFor("CREATE TABLE users;") {
+		t.Errorf("db.sql etag = %q, want %q", sql.ETag, etagFor("CREATE TABLE users;"))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "dumps", "db.sql"))
+	if err != nil || string(data) != "CREATE TABLE users;" {
+		t.Errorf("mirrored file content = %q, err = %v", data, err)
+	}
+
+	if statuses["../escape.txt"].Status != StatusFailed {
+		t.Errorf("path traversal key should fail, got %q", statuses["../escape.txt"].Status)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.txt")); err == nil {
+		t.Error("path traversal key was written outside the output directory")
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		t.Fatalf("manifest not written: %v", err)
+	}
+	var onDisk Manifest
+	if err := json.Unmarshal(raw, &onDisk); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v", err)
+	}
+	if onDisk.Bucket != "acme-public" {
+		t.Errorf("manifest bucket = %q, want %q", onDisk.Bucket, "acme-public")
+	}
+}
+
+func TestMirror_Run_Include(t *testing.T) {
+	fake := &fakeBucket{
+		name: "acme-public",
+		objects: map[string]string{
+			"a.sql": "one",
+			"b.txt": "two",
+		},
+	}
+	m, _ := newTestMirror(t, fake, &Config{OutputDir: t.TempDir(), Include: []string{"*.sql"}})
+
+	manifest, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(manifest.Entries) != 1 || manifest.Entries[0].Key != "a.sql" {
+		t.Errorf("Entries = %+v, want only a.sql", manifest.Entries)
+	}
+}
+
+func TestMirror_Run_MaxObjects(t *testing.T) {
+	fake := &fakeBucket{
+		name: "acme-public",
+		objects: map[string]string{
+			"a.txt": "1", "b.txt": "2", "c.txt": "3", "d.txt": "4",
+		},
+	}
+	m, _ := newTestMirror(t, fake, &Config{OutputDir: t.TempDir(), MaxObjects: 2})
+
+	manifest, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(manifest.Entries) != 2 {
+		t.Errorf("Entries = %d, want 2", len(manifest.Entries))
+	}
+}
+
+func TestMirror_Run_MaxTotalSize(t *testing.T) {
+	fake := &fakeBucket{
+		name: "acme-public",
+		objects: map[string]string{
+			"a-dump.sql": strings.Repeat("x", 100),
+			"b.txt":      "12345",
+			"c.txt":      "67890",
+			"d.txt":      "abcdef",
+		},
+	}
+	m, _ := newTestMirror(t, fake, &Config{OutputDir: t.TempDir(), MaxTotalSize: 12})
+
+	manifest, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var keys []string
+	for _, e := range manifest.Entries {
+		keys = append(keys, e.Key)
+	}
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "b.txt,c.txt" {
+		t.Errorf("Entries = %v, want b.txt and c.txt after skipping the large object", keys)
+	}
+	if manifest.Skipped != 2 {
+		t.Errorf("Skipped = %d, want 2", manifest.Skipped)
+	}
+}
+
+func TestMirror_Run_ResumeAndUnchanged(t *testing.T) {
+	body := "0123456789abcdefghij"
+	fake := &fakeBucket{
+		name:    "acme-public",
+		objects: map[string]string{"data.bin": body},
+	}
+	dir := t.TempDir()
+
+	// Leave a partial download behind
+	if err := os.WriteFile(filepath.Join(dir, "data.bin"+partSuffix), []byte(body[:8]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, _ := newTestMirror(t, fake, &Config{OutputDir: dir})
+	manifest, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if manifest.Entries[0].Status != StatusResumed {
+		t.Errorf("status = %q, want %q", manifest.Entries[0].Status, StatusResumed)
+	}
+	if fake.rangeHits.Load() != 1 {
+		t.Errorf("range requests = %d, want 1", fake.rangeHits.Load())
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "data.bin"))
+	if string(data) != body {
+		t.Errorf("resumed content = %q, want %q", data, body)
+	}
+
+	// Second run finds the object unchanged and does not fetch it again
+	fetches := fake.objectFetch.Load()
+	manifest, err = m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if manifest.Entries[0].Status != StatusUnchanged {
+		t.Errorf("second run status = %q, want %q", manifest.Entries[0].Status, StatusUnchanged)
+	}
+	if fake.objectFetch.Load() != fetches {
+		t.Error("unchanged object was downloaded again")
+	}
+}
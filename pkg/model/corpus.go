@@ -0,0 +1,57 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/xeloxa/s3finder/pkg/scanner"
+)
+
+// LoadCorpus reads bucket names from a file. JSON and TXT scan reports
+// contribute the buckets that were found; any other file is read as one
+// name per line.
+func LoadCorpus(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var report struct {
+			Results []*scanner.ScanResult `json:"results"`
+		}
+		if err := json.Unmarshal(trimmed, &report); err != nil {
+			return nil, err
+		}
+
+		var names []string
+		for _, r := range report.Results {
+			if r.Probe == scanner.BucketExists || r.Probe == scanner.BucketForbidden {
+				names = append(names, r.Bucket)
+			}
+		}
+		return names, nil
+	}
+
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// TXT report: "[PUBLIC] name | region: ..."
+		if strings.HasPrefix(line, "[") {
+			tag, rest, ok := strings.Cut(line, "] ")
+			if !ok || (tag != "[PUBLIC" && tag != "[PRIVATE") {
+				continue
+			}
+			line, _, _ = strings.Cut(rest, " |")
+		}
+		names = append(names, strings.ToLower(line))
+	}
+
+	return names, nil
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadCorpus(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "plain list",
+			content: "# buckets\nAcme-Logs\n\nglobex-data\n",
+			want:    []string{"acme-logs", "globex-data"},
+		},
+		{
+			name:    "txt report",
+			content: "[PUBLIC] acme-logs | region: us-east-1\n[PRIVATE] acme-data\n[ERROR] acme-broken\n",
+			want:    []string{"acme-logs", "acme-data"},
+		},
+		{
+			name: "json report",
+			content: `{"total_found": 2, "results": [
+				{"bucket": "acme-logs", "probe_result": 1},
+				{"bucket": "acme-data", "probe_result": 2},
+				{"bucket": "acme-broken", "probe_result": 3}
+			]}`,
+			want: []string{"acme-logs", "acme-data"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i)))
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadCorpus(path)
+			if err != nil {
+				t.Fatalf("LoadCorpus() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("LoadCorpus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
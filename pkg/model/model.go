@@ -0,0 +1,367 @@
+// Package model implements an offline n-gram model of bucket names.
+//
+// Names are split into words and separators. Words that are not part of the
+// naming vocabulary (environments, purposes, regions, ...) are treated as the
+// organisation's identifier and replaced by a seed placeholder, so a model
+// trained on acme-prod-logs and globex.backup.2024 generates rocket-prod-logs
+// or rocket.backup.2024 for the seed "rocket".
+package model
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/xeloxa/s3finder/pkg/permutation"
+)
+
+// Special tokens.
+const (
+	SeedToken  = "<seed>"
+	startToken = "<s>"
+	endToken   = "</s>"
+)
+
+const (
+	// backoffPenalty scales probabilities taken from a shorter context.
+	backoffPenalty = 0.4
+	// unseenLogProb is the log probability of a transition never observed.
+	unseenLogProb = -14.0
+	// maxTokens bounds the length of generated names in tokens.
+	maxTokens = 15
+)
+
+// Model is a token-level n-gram model.
+type Model struct {
+	Order       int                       `json:"order"`
+	Names       int                       `json:"names"` // Names the model was trained on
+	Vocabulary  []string                  `json:"vocabulary"`
+	Transitions map[string]map[string]int `json:"transitions"` // Context -> next token -> count
+
+	vocab map[string]struct{}
+}
+
+// TrainOptions configures training.
+type TrainOptions struct {
+	Order      int      // Tokens per n-gram, including the predicted one (default 3)
+	Vocabulary []string // Words kept literally (default DefaultVocabulary())
+}
+
+// DefaultVocabulary returns the words of all built-in permutation profiles.
+func DefaultVocabulary() []string {
+	seen := make(map[string]struct{})
+	var words []string
+	add := func(values ...string) {
+		for _, v := range values {
+			for _, w := range permutation.Tokenize(v) {
+				if _, ok := seen[w]; !ok {
+					seen[w] = struct{}{}
+					words = append(words, w)
+				}
+			}
+		}
+	}
+
+	add(permutation.DefaultEnvironments...)
+	for _, name := range permutation.ProfileNames() {
+		p, err := permutation.LoadProfile(name)
+		if err != nil {
+			continue
+		}
+		add(p.Prefixes...)
+		add(p.Suffixes...)
+		add(p.Regions...)
+		for _, values := range p.Dictionaries {
+			add(values...)
+		}
+	}
+
+	sort.Strings(words)
+	return words
+}
+
+// Train builds a model from bucket names.
+func Train(names []string, opts TrainOptions) *Model {
+	order := opts.Order
+	if order < 2 {
+		order = 3
+	}
+	vocabulary := opts.Vocabulary
+	if vocabulary == nil {
+		vocabulary = DefaultVocabulary()
+	}
+
+	m := &Model{
+		Order:       order,
+		Vocabulary:  vocabulary,
+		Transitions: make(map[string]map[string]int),
+	}
+	m.index()
+
+	for _, name := range names {
+		tokens := m.tokens(name, "")
+		if !containsSeed(tokens) {
+			continue
+		}
+		m.Names++
+
+		seq := m.pad(tokens)
+		for i := m.Order - 1; i < len(seq); i++ {
+			// Count every context length for backoff
+			for n := 1; n < m.Order; n++ {
+				ctx := strings.Join(seq[i-n:i], " ")
+				if m.Transitions[ctx] == nil {
+					m.Transitions[ctx] = make(map[string]int)
+				}
+				m.Transitions[ctx][seq[i]]++
+			}
+		}
+	}
+
+	return m
+}
+
+// Load reads a model file written by Save.
+func Load(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid model file: %w", err)
+	}
+	if m.Order < 2 || m.Transitions == nil {
+		return nil, fmt.Errorf("invalid model file: missing transitions")
+	}
+	m.index()
+
+	return &m, nil
+}
+
+// Save writes the model as JSON.
+func (m *Model) Save(path string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Generate returns up to n names for the seed, most probable first.
+func (m *Model) Generate(seed string, n int) []string {
+	seed = strings.ToLower(strings.TrimSpace(seed))
+	if seed == "" || n <= 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var names []string
+
+	pq := &queue{{tokens: m.pad(nil)[:m.Order-1]}}
+	for expanded := 0; pq.Len() > 0 && len(names) < n && expanded < n*200; expanded++ {
+		cur := heap.Pop(pq).(*candidate)
+
+		if cur.tokens[len(cur.tokens)-1] == endToken {
+			name := m.render(cur.tokens, seed)
+			if _, ok := seen[name]; !ok && permutation.IsValidBucketName(name) {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+			continue
+		}
+		if len(cur.tokens) > maxTokens+m.Order {
+			continue
+		}
+
+		ctx := strings.Join(cur.tokens[len(cur.tokens)-(m.Order-1):], " ")
+		next := m.Transitions[ctx]
+		total := sumCounts(next)
+		for token, count := range next {
+			// A name must mention the seed before it ends
+			if token == endToken && !containsSeed(cur.tokens) {
+				continue
+			}
+			tokens := append(append([]string(nil), cur.tokens...), token)
+			heap.Push(pq, &candidate{
+				tokens:  tokens,
+				logProb: cur.logProb + math.Log(float64(count)/float64(total)),
+			})
+		}
+	}
+
+	return names
+}
+
+// Score returns the average log probability per token of name, treating
+// seed as the organisation's identifier. Higher is more likely.
+func (m *Model) Score(name, seed string) float64 {
+	tokens := m.tokens(name, strings.ToLower(strings.TrimSpace(seed)))
+	seq := m.pad(tokens)
+
+	var total float64
+	steps := 0
+	for i := m.Order - 1; i < len(seq); i++ {
+		total += m.logProb(seq[i-(m.Order-1):i], seq[i])
+		steps++
+	}
+	if steps == 0 {
+		return unseenLogProb
+	}
+	return total / float64(steps)
+}
+
+// logProb estimates P(token | ctx) with stupid backoff to shorter contexts.
+func (m *Model) logProb(ctx []string, token string) float64 {
+	penalty := 0.0
+	for n := len(ctx); n >= 1; n-- {
+		counts := m.Transitions[strings.Join(ctx[len(ctx)-n:], " ")]
+		if c := counts[token]; c > 0 {
+			return penalty + math.Log(float64(c)/float64(sumCounts(counts)))
+		}
+		penalty += math.Log(backoffPenalty)
+	}
+	return unseenLogProb
+}
+
+// tokens splits name into words and separators. Vocabulary words, digits and
+// separators are kept; other words become SeedToken, with runs of them
+// merged (acme-corp-logs -> <seed> - logs). If seed is given, its words are
+// always mapped to SeedToken.
+func (m *Model) tokens(name, seed string) []string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if seed != "" {
+		name = strings.ReplaceAll(name, seed, "\x00")
+	}
+
+	var raw []string
+	start := 0
+	for i := 0; i <= len(name); i++ {
+		if i == len(name) || name[i] == '-' || name[i] == '.' {
+			if i > start {
+				raw = append(raw, name[start:i])
+			}
+			if i < len(name) {
+				raw = append(raw, name[i:i+1])
+			}
+			start = i + 1
+		}
+	}
+
+	var tokens []string
+	for i := 0; i < len(raw); i++ {
+		tok := raw[i]
+		if tok != "-" && tok != "." && !m.isLiteral(tok) {
+			tok = SeedToken
+		}
+
+		// Merge "<seed> - <seed>" into one identifier
+		if tok == SeedToken && len(tokens) >= 2 &&
+			isSep(tokens[len(tokens)-1]) && tokens[len(tokens)-2] == SeedToken {
+			tokens = tokens[:len(tokens)-1]
+			continue
+		}
+		if tok == SeedToken && len(tokens) > 0 && tokens[len(tokens)-1] == SeedToken {
+			continue
+		}
+		tokens = append(tokens, tok)
+	}
+
+	return tokens
+}
+
+// isLiteral reports whether a word is kept as-is rather than abstracted.
+func (m *Model) isLiteral(word string) bool {
+	if strings.Contains(word, "\x00") {
+		return false
+	}
+	if _, ok := m.vocab[word]; ok {
+		return true
+	}
+	return strings.Trim(word, "0123456789") == "" || len(word) == 1
+}
+
+// pad surrounds tokens with start and end markers.
+func (m *Model) pad(tokens []string) []string {
+	seq := make([]string, 0, len(tokens)+m.Order)
+	for i := 0; i < m.Order-1; i++ {
+		seq = append(seq, startToken)
+	}
+	seq = append(seq, tokens...)
+	return append(seq, endToken)
+}
+
+// render turns a generated token sequence into a bucket name.
+func (m *Model) render(tokens []string, seed string) string {
+	var b strings.Builder
+	for _, tok := range tokens {
+		switch tok {
+		case startToken, endToken:
+		case SeedToken:
+			b.WriteString(seed)
+		default:
+			b.WriteString(tok)
+		}
+	}
+	return b.String()
+}
+
+// index builds the vocabulary lookup.
+func (m *Model) index() {
+	m.vocab = make(map[string]struct{}, len(m.Vocabulary))
+	for _, w := range m.Vocabulary {
+		m.vocab[w] = struct{}{}
+	}
+}
+
+func containsSeed(tokens []string) bool {
+	for _, t := range tokens {
+		if t == SeedToken {
+			return true
+		}
+	}
+	return false
+}
+
+func isSep(tok string) bool {
+	return tok == "-" || tok == "."
+}
+
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	return total
+}
+
+// candidate is a partial name in the best-first search.
+type candidate struct {
+	tokens  []string
+	logProb float64
+}
+
+// queue is a max-heap of candidates by probability.
+type queue []*candidate
+
+func (q queue) Len() int { return len(q) }
+func (q queue) Less(i, j int) bool {
+	if q[i].logProb != q[j].logProb {
+		return q[i].logProb > q[j].logProb
+	}
+	// Deterministic order for equal probabilities
+	return strings.Join(q[i].tokens, "") < strings.Join(q[j].tokens, "")
+}
+func (q queue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x any)   { *q = append(*q, x.(*candidate)) }
+func (q *queue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
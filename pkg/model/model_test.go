@@ -0,0 +1,110 @@
+package model
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+var corpus = []string{
+	"acme-prod-logs",
+	"acme-staging-logs",
+	"globex-prod-logs",
+	"globex.backup.2024",
+	"initech-prod-assets",
+	"initech-dev-assets",
+	"hooli-data-backup",
+}
+
+func TestTokens(t *testing.T) {
+	m := Train(nil, TrainOptions{})
+
+	tests := []struct {
+		name string
+		seed string
+		want []string
+	}{
+		{"acme-prod-logs", "", []string{SeedToken, "-", "prod", "-", "logs"}},
+		{"acme-corp-widget-logs", "", []string{SeedToken, "-", "corp", "-", SeedToken, "-", "logs"}},
+		{"foo-bar-logs", "", []string{SeedToken, "-", "logs"}},
+		{"globex.backup.2024", "", []string{SeedToken, ".", "backup", ".", "2024"}},
+		{"rocket-prod", "rocket", []string{SeedToken, "-", "prod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.tokens(tt.name, tt.seed); !slices.Equal(got, tt.want) {
+				t.Errorf("tokens(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrain_SkipsNamesWithoutIdentifier(t *testing.T) {
+	m := Train([]string{"prod-logs", "acme-logs"}, TrainOptions{})
+	if m.Names != 1 {
+		t.Errorf("Names = %d, want 1", m.Names)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	m := Train(corpus, TrainOptions{})
+	names := m.Generate("rocket", 10)
+
+	if len(names) == 0 {
+		t.Fatal("Generate() returned no names")
+	}
+	if names[0] != "rocket-prod-logs" {
+		t.Errorf("Generate()[0] = %q, want the most common pattern rocket-prod-logs", names[0])
+	}
+	for _, want := range []string{"rocket-staging-logs", "rocket.backup.2024", "rocket-dev-assets"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Generate() missing %q", want)
+		}
+	}
+	for _, name := range names {
+		if !slices.Contains(m.tokens(name, "rocket"), SeedToken) {
+			t.Errorf("Generate() produced %q without the seed", name)
+		}
+	}
+}
+
+func TestScore_RanksSeenPatternsHigher(t *testing.T) {
+	m := Train(corpus, TrainOptions{})
+
+	likely := m.Score("rocket-prod-logs", "rocket")
+	unlikely := m.Score("logs-rocket-2024-prod", "rocket")
+	if likely <= unlikely {
+		t.Errorf("Score(likely) = %.3f, Score(unlikely) = %.3f; want likely higher", likely, unlikely)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+
+	m := Train(corpus, TrainOptions{Order: 2})
+	if err := m.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Order != 2 || loaded.Names != m.Names {
+		t.Errorf("Load() order=%d names=%d, want 2/%d", loaded.Order, loaded.Names, m.Names)
+	}
+	if !slices.Equal(loaded.Generate("rocket", 5), m.Generate("rocket", 5)) {
+		t.Error("loaded model generates different names")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := (&Model{}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() expected error for empty model")
+	}
+}
@@ -30,6 +30,7 @@ type ProgressConfig struct {
 	UseColors     bool
 	BarWidth      int
 	ExternalMu    *sync.Mutex // Optional external mutex for synchronization
+	ShowInspect   bool        // Show deep inspection queue depth
 }
 
 // Progress displays real-time scanning progress.
@@ -41,6 +42,7 @@ type Progress struct {
 	public       atomic.Int64
 	private      atomic.Int64
 	errors       atomic.Int64
+	inspectQueue atomic.Int64
 	currentRPS   atomic.Value // float64
 	startTime    time.Time
 	stopChan     chan struct{}
@@ -79,6 +81,7 @@ func NewProgress(cfg *ProgressConfig) *Progress {
 			ShowRPS:     cfg.ShowRPS,
 			UseColors:   cfg.UseColors,
 			BarWidth:    barWidth,
+			ShowInspect: cfg.ShowInspect,
 		},
 		total:     cfg.Total,
 		startTime: time.Now(),
@@ -112,6 +115,11 @@ func (p *Progress) Update(scanned, found, public, private, errors int64, rps flo
 	p.currentRPS.Store(rps)
 }
 
+// SetInspectQueue updates the number of buckets waiting for deep inspection.
+func (p *Progress) SetInspectQueue(depth int64) {
+	p.inspectQueue.Store(depth)
+}
+
 // Increment increments a specific counter.
 func (p *Progress) Increment(counter string) {
 	switch counter {
@@ -232,6 +240,16 @@ func (p *Progress) renderUnlocked(final bool) {
 		)
 	}
 
+	// Add inspection backlog
+	if p.cfg.ShowInspect {
+		queue := p.inspectQueue.Load()
+		if p.cfg.UseColors {
+			statsLine = fmt.Sprintf("%s %sInspectQ:%s%d%s", statsLine, progressColorLabel, progressColorValue, queue, progressColorReset)
+		} else {
+			statsLine = fmt.Sprintf("%s InspectQ:%d", statsLine, queue)
+		}
+	}
+
 	// Add elapsed time
 	elapsedStr := formatDuration(elapsed)
 	statsLine = fmt.Sprintf("%s [%s]", statsLine, elapsedStr)
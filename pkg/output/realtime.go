@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -152,6 +153,14 @@ func (r *RealtimeWriter) formatPublic(result *scanner.ScanResult) string {
 		urlLine = fmt.Sprintf("\n         %s", bucketURL)
 	}
 
+	// Archive contents
+	archiveLines := ""
+	if result.Inspect != nil {
+		for _, archive := range result.Inspect.Archives {
+			archiveLines += "\n         " + r.formatArchive(archive)
+		}
+	}
+
 	warningLine := ""
 	if result.Warning != "" {
 		if r.useColors {
@@ -161,7 +170,41 @@ func (r *RealtimeWriter) formatPublic(result *scanner.ScanResult) string {
 		}
 	}
 
-	return fmt.Sprintf("%s %s%s%s%s", tag, bucketDisplay, details, urlLine, warningLine)
+	return fmt.Sprintf("%s %s%s%s%s%s%s", tag, bucketDisplay, details, urlLine, r.formatOrigin(result), archiveLines, warningLine)
+}
+
+// formatArchive summarizes a peeked archive on one line.
+func (r *RealtimeWriter) formatArchive(archive scanner.ArchiveInfo) string {
+	if archive.Error != "" && len(archive.Entries) == 0 {
+		line := fmt.Sprintf("↳ %s: %s", archive.Key, archive.Error)
+		if r.useColors {
+			line = colorGray + line + colorReset
+		}
+		return line
+	}
+
+	count := fmt.Sprintf("%d", len(archive.Entries))
+	if archive.Truncated {
+		count += "+"
+	}
+	line := fmt.Sprintf("↳ %s: %s entries", archive.Key, count)
+
+	if len(archive.Sensitive) > 0 {
+		names := make([]string, 0, len(archive.Sensitive))
+		for _, s := range archive.Sensitive[:min(5, len(archive.Sensitive))] {
+			names = append(names, s.Name)
+		}
+		sensitive := fmt.Sprintf(", %d sensitive: %s", len(archive.Sensitive), strings.Join(names, ", "))
+		if r.useColors {
+			return colorGray + line + colorReset + colorRed + sensitive + colorReset
+		}
+		return line + sensitive
+	}
+
+	if r.useColors {
+		line = colorGray + line + colorReset
+	}
+	return line
 }
 
 func (r *RealtimeWriter) formatPrivate(result *scanner.ScanResult) string {
@@ -180,10 +223,40 @@ func (r *RealtimeWriter) formatPrivate(result *scanner.ScanResult) string {
 	}
 
 	details := ""
-	if result.Inspect != nil && result.Inspect.Region != "" && result.Inspect.Region != "unknown" {
-		details = fmt.Sprintf(" (region: %s)", result.Inspect.Region)
-		if r.useColors {
-			details = colorGray + details + colorReset
+	if result.Inspect != nil {
+		var parts []string
+		if result.Inspect.Region != "" && result.Inspect.Region != "unknown" {
+			parts = append(parts, fmt.Sprintf("region: %s", result.Inspect.Region))
+		}
+		// Surface notable access states such as all access disabled
+		switch result.Inspect.State {
+		case scanner.AccessUnknown, scanner.AccessPrivate, scanner.AccessPublic:
+		default:
+			parts = append(parts, result.Inspect.State.String())
+		}
+		if len(parts) > 0 {
+			details = fmt.Sprintf(" (%s)", strings.Join(parts, ", "))
+			if r.useColors {
+				details = colorGray + details + colorReset
+			}
+		}
+	}
+
+	// Objects readable despite denied listing
+	objectLines := ""
+	if result.Inspect != nil {
+		for _, obj := range result.Inspect.ReadableObjects {
+			objURL := fmt.Sprintf("%s/%s", bucketURL, obj.Key)
+			line := fmt.Sprintf("%s (%d bytes", objURL, obj.Size)
+			if obj.ContentType != "" {
+				line += ", " + obj.ContentType
+			}
+			line += ")"
+			if r.useColors {
+				objectLines += fmt.Sprintf("\n         %s↳ %s%s", colorGreen, line, colorReset)
+			} else {
+				objectLines += fmt.Sprintf("\n         Readable: %s", line)
+			}
 		}
 	}
 
@@ -196,7 +269,18 @@ func (r *RealtimeWriter) formatPrivate(result *scanner.ScanResult) string {
 		}
 	}
 
-	return fmt.Sprintf("%s %s%s%s", tag, bucketDisplay, details, warningLine)
+	return fmt.Sprintf("%s %s%s%s%s%s", tag, bucketDisplay, details, r.formatOrigin(result), objectLines, warningLine)
+}
+
+// formatOrigin names where the bucket was found, if known.
+func (r *RealtimeWriter) formatOrigin(result *scanner.ScanResult) string {
+	if result.Origin == "" {
+		return ""
+	}
+	if r.useColors {
+		return fmt.Sprintf("\n         %sfound via %s%s", colorGray, result.Origin, colorReset)
+	}
+	return fmt.Sprintf("\n         Found via: %s", result.Origin)
 }
 
 // makeHyperlink creates an OSC 8 terminal hyperlink
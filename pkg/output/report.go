@@ -127,16 +127,34 @@ func (r *ReportWriter) flushTXT() error {
 			continue
 		}
 
+		if result.Origin != "" {
+			line += fmt.Sprintf(" | via: %s", result.Origin)
+		}
 		if result.Inspect != nil {
 			if result.Inspect.Region != "" && result.Inspect.Region != "unknown" {
 				line += fmt.Sprintf(" | region: %s", result.Inspect.Region)
 			}
+			switch result.Inspect.State {
+			case scanner.AccessUnknown, scanner.AccessPrivate, scanner.AccessPublic:
+			default:
+				line += fmt.Sprintf(" | state: %s", result.Inspect.State)
+			}
 			if result.Inspect.ObjectCount > 0 {
 				line += fmt.Sprintf(" | objects: %d", result.Inspect.ObjectCount)
 			}
 			if len(result.Inspect.SampleKeys) > 0 {
 				line += fmt.Sprintf(" | sample: %v", result.Inspect.SampleKeys[:min(3, len(result.Inspect.SampleKeys))])
 			}
+			for _, archive := range result.Inspect.Archives {
+				line += fmt.Sprintf(" | archive %s: %d entries, %d sensitive", archive.Key, len(archive.Entries), len(archive.Sensitive))
+			}
+			if len(result.Inspect.ReadableObjects) > 0 {
+				keys := make([]string, 0, len(result.Inspect.ReadableObjects))
+				for _, obj := range result.Inspect.ReadableObjects {
+					keys = append(keys, obj.Key)
+				}
+				line += fmt.Sprintf(" | readable: %v", keys)
+			}
 		}
 
 		fmt.Fprintln(r.file, line)
@@ -171,8 +171,10 @@ func TestReportWriter_FlushTXT(t *testing.T) {
 	rw.WriteResult(&scanner.ScanResult{
 		Bucket: "private-bucket",
 		Probe:  scanner.BucketForbidden,
+		Origin: "assets.acme.com",
 		Inspect: &scanner.InspectResult{
-			Region: "eu-west-1",
+			Region:          "eu-west-1",
+			ReadableObjects: []scanner.ObjectInfo{{Key: "index.html", Size: 42}},
 		},
 	})
 	rw.Close()
@@ -196,6 +198,12 @@ func TestReportWriter_FlushTXT(t *testing.T) {
 	if !strings.Contains(content, "objects: 100") {
 		t.Error("TXT report should contain object count")
 	}
+	if !strings.Contains(content, "readable: [index.html]") {
+		t.Error("TXT report should contain readable objects")
+	}
+	if !strings.Contains(content, "[PRIVATE] private-bucket | via: assets.acme.com") {
+		t.Error("TXT report should contain the origin")
+	}
 }
 
 func TestReportWriter_FlushTXT_SkipsNotFound(t *testing.T) {
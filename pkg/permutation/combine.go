@@ -0,0 +1,79 @@
+package permutation
+
+import "strings"
+
+// NormalizeSeeds lowercases and trims seeds, dropping empties and duplicates
+// while keeping the original order.
+func NormalizeSeeds(seeds []string) []string {
+	seen := make(map[string]struct{})
+	var out []string
+	for _, s := range seeds {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		if _, ok := seen[s]; !ok {
+			seen[s] = struct{}{}
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Combine joins every ordered pair of distinct seeds (acme-rocket,
+// rocket.acme, acmerocket) and then runs the affix permutations on each
+// pair. Bare pairs come first so they survive the cap. At most limit names
+// are returned (0 = unlimited).
+func (e *Engine) Combine(seeds []string, limit int) []string {
+	seeds = NormalizeSeeds(seeds)
+	if len(seeds) < 2 {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var results []string
+
+	full := func() bool {
+		return limit > 0 && len(results) >= limit
+	}
+	add := func(name string) {
+		if full() {
+			return
+		}
+		if _, ok := seen[name]; !ok && IsValidBucketName(name) {
+			seen[name] = struct{}{}
+			results = append(results, name)
+		}
+	}
+
+	joiners := append([]string{""}, e.Separators...)
+
+	var pairs []string
+	for _, a := range seeds {
+		for _, b := range seeds {
+			if a == b {
+				continue
+			}
+			for _, sep := range joiners {
+				pairs = append(pairs, a+sep+b)
+			}
+		}
+	}
+
+	// Bare pairs
+	for _, pair := range pairs {
+		add(pair)
+	}
+
+	// Pairs with the engine's affixes
+	for _, pair := range pairs {
+		if full() {
+			break
+		}
+		for _, name := range e.Generate(pair) {
+			add(name)
+		}
+	}
+
+	return results
+}
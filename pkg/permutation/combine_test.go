@@ -0,0 +1,55 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeSeeds(t *testing.T) {
+	got := NormalizeSeeds([]string{" Acme ", "rocket", "", "ACME", "rocket"})
+	want := []string{"acme", "rocket"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("NormalizeSeeds() = %v, want %v", got, want)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	e := Default()
+	names := e.Combine([]string{"acme", "rocket"}, 0)
+
+	for _, want := range []string{"acme-rocket", "rocket.acme", "acmerocket", "acme-rocket-dev", "dev-rocket-acme"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Combine() missing %q", want)
+		}
+	}
+
+	// Bare pairs lead the output
+	if !slices.Contains(names[:6], "acme-rocket") {
+		t.Errorf("Combine() bare pairs not first: %v", names[:6])
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("Combine() duplicate %q", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestCombine_Limit(t *testing.T) {
+	e := Default()
+	names := e.Combine([]string{"acme", "rocket", "widget"}, 50)
+
+	if len(names) != 50 {
+		t.Errorf("Combine() returned %d names, want 50", len(names))
+	}
+}
+
+func TestCombine_SingleSeed(t *testing.T) {
+	e := Default()
+	if names := e.Combine([]string{"acme", "ACME"}, 0); names != nil {
+		t.Errorf("Combine() with one distinct seed = %v, want nil", names)
+	}
+}
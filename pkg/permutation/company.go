@@ -0,0 +1,125 @@
+package permutation
+
+import "strings"
+
+// legalSuffixes are company form designators that never appear in bucket
+// names. They are dropped wherever they occur at the end of a name.
+var legalSuffixes = map[string]struct{}{
+	"inc": {}, "incorporated": {}, "corp": {}, "corporation": {}, "co": {},
+	"company": {}, "llc": {}, "llp": {}, "lp": {}, "ltd": {}, "limited": {},
+	"plc": {}, "pty": {}, "pte": {}, "gmbh": {}, "ag": {}, "kg": {}, "se": {},
+	"sa": {}, "sas": {}, "sarl": {}, "srl": {}, "spa": {}, "bv": {}, "nv": {},
+	"ab": {}, "as": {}, "asa": {}, "oy": {}, "oyj": {}, "kk": {}, "sl": {},
+	"ltda": {}, "sro": {},
+}
+
+// descriptorWords describe a company rather than name it. They are kept in
+// the full form and the acronym (Acme Widgets International -> awi) but
+// also trimmed from the end for the shorter core form (acme-widgets).
+var descriptorWords = map[string]struct{}{
+	"international": {}, "group": {}, "holdings": {}, "holding": {},
+	"global": {}, "worldwide": {}, "enterprises": {}, "industries": {},
+	"technologies": {}, "technology": {}, "solutions": {}, "systems": {},
+	"services": {}, "labs": {}, "partners": {},
+}
+
+// IsCompanyName reports whether seed looks like a company name ("Acme
+// Widgets, Inc.") rather than a bucket keyword ("acme-corp"), i.e. whether
+// it contains characters that are not allowed in bucket names.
+func IsCompanyName(seed string) bool {
+	for _, r := range strings.ToLower(strings.TrimSpace(seed)) {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return true
+		}
+	}
+	return false
+}
+
+// CompanyForms returns the seed forms of a company name, most specific
+// first: hyphenated and concatenated core words, the acronym, and the same
+// for the full name when descriptors were trimmed. Legal suffixes are
+// always stripped, so "Acme Widgets International, Inc." yields
+// acme-widgets, acmewidgets, awi, acme-widgets-international,
+// acmewidgetsinternational and aw.
+func CompanyForms(name string) []string {
+	full := companyWords(name)
+
+	// Trim legal suffixes (Acme Holdings Co. Ltd.)
+	for len(full) > 1 {
+		if _, ok := legalSuffixes[full[len(full)-1]]; !ok {
+			break
+		}
+		full = full[:len(full)-1]
+	}
+	if len(full) == 0 {
+		return nil
+	}
+
+	core := full
+	for len(core) > 1 {
+		if _, ok := descriptorWords[core[len(core)-1]]; !ok {
+			break
+		}
+		core = core[:len(core)-1]
+	}
+
+	seen := make(map[string]struct{})
+	var forms []string
+	add := func(form string) {
+		if _, ok := seen[form]; !ok && form != "" {
+			seen[form] = struct{}{}
+			forms = append(forms, form)
+		}
+	}
+
+	add(strings.Join(core, "-"))
+	add(strings.Join(core, ""))
+	if len(full) > 1 {
+		add(acronym(full))
+	}
+	if len(full) > len(core) {
+		add(strings.Join(full, "-"))
+		add(strings.Join(full, ""))
+		if len(core) > 1 {
+			add(acronym(core))
+		}
+	}
+
+	return forms
+}
+
+// ExpandSeeds transliterates non-ASCII seeds, replaces company-style seeds
+// with their CompanyForms and normalizes the result. Keyword seeds are kept
+// as they are.
+func ExpandSeeds(seeds []string) []string {
+	var out []string
+	for _, s := range seeds {
+		for _, v := range Transliterate(strings.TrimSpace(s)) {
+			if IsCompanyName(v) {
+				out = append(out, CompanyForms(v)...)
+			} else {
+				out = append(out, v)
+			}
+		}
+	}
+	return NormalizeSeeds(out)
+}
+
+// companyWords lowercases a name and splits it into alphanumeric words.
+// Apostrophes are dropped (McDonald's -> mcdonalds) and "&" separates words.
+func companyWords(name string) []string {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("'", "", "’", "").Replace(name)
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// acronym joins the first letter of each word.
+func acronym(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		b.WriteByte(w[0])
+	}
+	return b.String()
+}
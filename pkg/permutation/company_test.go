@@ -0,0 +1,94 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsCompanyName(t *testing.T) {
+	tests := []struct {
+		seed     string
+		expected bool
+	}{
+		{"acme", false},
+		{"acme-corp", false},
+		{"Acme", false},
+		{"acme.io", false},
+		{"Acme Widgets", true},
+		{"Acme, Inc.", true},
+		{"AT&T", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsCompanyName(tt.seed); got != tt.expected {
+			t.Errorf("IsCompanyName(%q) = %v, want %v", tt.seed, got, tt.expected)
+		}
+	}
+}
+
+func TestCompanyForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			"legal suffix and descriptor",
+			"Acme Widgets International, Inc.",
+			[]string{"acme-widgets", "acmewidgets", "awi", "acme-widgets-international", "acmewidgetsinternational", "aw"},
+		},
+		{
+			"several legal suffixes",
+			"Globex Co. Ltd.",
+			[]string{"globex"},
+		},
+		{
+			"ampersand",
+			"Procter & Gamble Company",
+			[]string{"procter-gamble", "proctergamble", "pg"},
+		},
+		{
+			"apostrophe",
+			"McDonald's Corporation",
+			[]string{"mcdonalds"},
+		},
+		{
+			"descriptor only",
+			"Group Inc",
+			[]string{"group"},
+		},
+		{
+			"three words",
+			"International Business Machines Corp",
+			[]string{"international-business-machines", "internationalbusinessmachines", "ibm"},
+		},
+		{
+			"empty",
+			"Inc.",
+			[]string{"inc"},
+		},
+		{
+			"punctuation only",
+			", .",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompanyForms(tt.input)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("CompanyForms(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandSeeds(t *testing.T) {
+	got := ExpandSeeds([]string{"acme-corp", "Initech LLC", "ROCKET", "initech"})
+	want := []string{"acme-corp", "initech", "rocket"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("ExpandSeeds() = %v, want %v", got, want)
+	}
+}
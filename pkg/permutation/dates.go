@@ -0,0 +1,143 @@
+package permutation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Date formats understood by DateRange.
+const (
+	DateYYYY       = "yyyy"    // 2026
+	DateYY         = "yy"      // 26
+	DateYYYYMM     = "yyyymm"  // 202603
+	DateYYYYDashMM = "yyyy-mm" // 2026-03
+	DateQuarter    = "quarter" // q1-2026
+	DateFiscal     = "fiscal"  // fy2026, fy26
+)
+
+// DateFormats lists every supported date format.
+var DateFormats = []string{DateYYYY, DateYY, DateYYYYMM, DateYYYYDashMM, DateQuarter, DateFiscal}
+
+// DefaultDateFormats are used when no formats are configured.
+var DefaultDateFormats = []string{DateYYYY, DateYY}
+
+// DateRange generates date tokens relative to a reference date.
+type DateRange struct {
+	Now     time.Time // Reference date (zero = time.Now())
+	Back    int       // Years before Now
+	Forward int       // Years after Now
+	Formats []string  // Formats to produce (empty = DefaultDateFormats)
+	// FiscalStart is the first month of the fiscal year, which is named after
+	// the calendar year it ends in. Zero means January.
+	FiscalStart time.Month
+}
+
+// DefaultDateRange covers the last three years and the next one.
+func DefaultDateRange() DateRange {
+	return DateRange{Back: 3, Forward: 1}
+}
+
+// Validate reports unknown formats.
+func (d DateRange) Validate() error {
+	for _, f := range d.Formats {
+		if !isDateFormat(f) {
+			return fmt.Errorf("unknown date format %q (supported: %s)", f, strings.Join(DateFormats, ", "))
+		}
+	}
+	return nil
+}
+
+// Values returns the date tokens without separators, newest year last.
+func (d DateRange) Values() []string {
+	now := d.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	formats := d.Formats
+	if len(formats) == 0 {
+		formats = DefaultDateFormats
+	}
+
+	first, last := now.Year()-max(d.Back, 0), now.Year()+max(d.Forward, 0)
+
+	seen := make(map[string]struct{})
+	var values []string
+	add := func(v string) {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			values = append(values, v)
+		}
+	}
+
+	for _, format := range formats {
+		switch strings.ToLower(format) {
+		case DateYYYY:
+			for y := first; y <= last; y++ {
+				add(fmt.Sprintf("%04d", y))
+			}
+		case DateYY:
+			for y := first; y <= last; y++ {
+				add(fmt.Sprintf("%02d", y%100))
+			}
+		case DateYYYYMM:
+			for y := first; y <= last; y++ {
+				for m := 1; m <= 12; m++ {
+					add(fmt.Sprintf("%04d%02d", y, m))
+				}
+			}
+		case DateYYYYDashMM:
+			for y := first; y <= last; y++ {
+				for m := 1; m <= 12; m++ {
+					add(fmt.Sprintf("%04d-%02d", y, m))
+				}
+			}
+		case DateQuarter:
+			for y := first; y <= last; y++ {
+				for q := 1; q <= 4; q++ {
+					add(fmt.Sprintf("q%d-%04d", q, y))
+				}
+			}
+		case DateFiscal:
+			fy := fiscalYear(now, d.FiscalStart)
+			for y := fy - max(d.Back, 0); y <= fy+max(d.Forward, 0); y++ {
+				add(fmt.Sprintf("fy%04d", y))
+				add(fmt.Sprintf("fy%02d", y%100))
+			}
+		}
+	}
+
+	return values
+}
+
+// Suffixes returns the date tokens as engine suffixes: "" followed by
+// "-token" for each value.
+func (d DateRange) Suffixes() []string {
+	suffixes := []string{""}
+	for _, v := range d.Values() {
+		suffixes = append(suffixes, "-"+v)
+	}
+	return suffixes
+}
+
+// fiscalYear returns the fiscal year containing t for a year starting in
+// month start, named after the calendar year it ends in.
+func fiscalYear(t time.Time, start time.Month) int {
+	if start <= time.January || start > time.December {
+		return t.Year()
+	}
+	if t.Month() >= start {
+		return t.Year() + 1
+	}
+	return t.Year()
+}
+
+// isDateFormat reports whether f is a supported format.
+func isDateFormat(f string) bool {
+	for _, known := range DateFormats {
+		if strings.EqualFold(f, known) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDateRange_Values(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		d       DateRange
+		want    []string
+		missing []string
+	}{
+		{
+			name: "default formats",
+			d:    DateRange{Now: now, Back: 1, Forward: 1},
+			want: []string{"2025", "2026", "2027", "25", "26", "27"},
+		},
+		{
+			name: "months",
+			d:    DateRange{Now: now, Formats: []string{DateYYYYMM, DateYYYYDashMM}},
+			want: []string{"202601", "202612", "2026-01", "2026-12"},
+		},
+		{
+			name: "quarters",
+			d:    DateRange{Now: now, Back: 1, Formats: []string{DateQuarter}},
+			want: []string{"q1-2025", "q4-2025", "q1-2026", "q4-2026"},
+		},
+		{
+			name:    "calendar fiscal year",
+			d:       DateRange{Now: now, Formats: []string{DateFiscal}},
+			want:    []string{"fy2026", "fy26"},
+			missing: []string{"fy2027"},
+		},
+		{
+			name: "fiscal year starting in october",
+			d:    DateRange{Now: time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC), Formats: []string{DateFiscal}, FiscalStart: time.October},
+			want: []string{"fy2027", "fy27"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.d.Values()
+			for _, w := range tt.want {
+				if !slices.Contains(got, w) {
+					t.Errorf("Values() missing %q, got %v", w, got)
+				}
+			}
+			for _, m := range tt.missing {
+				if slices.Contains(got, m) {
+					t.Errorf("Values() unexpectedly contains %q", m)
+				}
+			}
+		})
+	}
+}
+
+func TestDateRange_Suffixes(t *testing.T) {
+	d := DateRange{Now: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), Formats: []string{DateYYYY}}
+	got := d.Suffixes()
+	want := []string{"", "-2026"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Suffixes() = %v, want %v", got, want)
+	}
+}
+
+func TestDateRange_Validate(t *testing.T) {
+	if err := (DateRange{Formats: []string{"yyyy", "Quarter"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (DateRange{Formats: []string{"mm-yyyy"}}).Validate(); err == nil {
+		t.Error("Validate() expected error for unknown format")
+	}
+}
+
+func TestDefault_YearsFollowCurrentDate(t *testing.T) {
+	year := time.Now().Year()
+	e := Default()
+
+	for _, y := range []int{year - 3, year, year + 1} {
+		want := "-" + time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006")
+		if !slices.Contains(e.Years, want) {
+			t.Errorf("Default().Years missing %q", want)
+		}
+	}
+}
+
+func TestTemplate_DateSlot(t *testing.T) {
+	e := &Engine{
+		Separators: []string{"-"},
+		Years:      DateRange{Now: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Formats: []string{DateQuarter}}.Suffixes(),
+	}
+	if err := e.AddTemplate("{seed}{sep}{year}"); err != nil {
+		t.Fatalf("AddTemplate() error = %v", err)
+	}
+
+	if !slices.Contains(e.Generate("acme"), "acme-q2-2026") {
+		t.Error("Generate() missing templated quarter acme-q2-2026")
+	}
+}
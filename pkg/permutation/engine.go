@@ -14,6 +14,16 @@ type Engine struct {
 	Separators []string
 	Years      []string
 	Regions    []string
+
+	// Templates are expanded for every seed in addition to the built-in
+	// combinations. Dictionaries adds or overrides template dictionaries.
+	Templates     []*Template
+	Dictionaries  map[string][]string
+	TemplateLimit int // Max names per template per seed (0 = unlimited)
+
+	// Mutators add human variations of the seed (typos, leet, ...).
+	Mutators    []Mutator
+	MutateLimit int // Max mutated seed variants per seed (0 = unlimited)
 }
 
 // Default returns an Engine with common AWS naming patterns.
@@ -31,15 +41,14 @@ func Default() *Engine {
 			"internal-", "public-", "private-", "temp-", "old-",
 		},
 		Separators: []string{"-", "."},
-		Years: []string{
-			"", "-2022", "-2023", "-2024", "-2025",
-			"-22", "-23", "-24", "-25",
-		},
+		Years:      DefaultDateRange().Suffixes(),
 		Regions: []string{
 			"", "-us-east-1", "-us-east-2", "-us-west-1", "-us-west-2",
 			"-eu-west-1", "-eu-west-2", "-eu-central-1",
 			"-ap-south-1", "-ap-northeast-1", "-ap-southeast-1",
 		},
+		TemplateLimit: 10000,
+		MutateLimit:   200,
 	}
 }
 
@@ -50,6 +59,21 @@ func (e *Engine) Generate(seed string) []string {
 		return nil
 	}
 
+	// Non-ASCII seeds are permuted once per transliteration
+	if !isASCII(seed) {
+		seen := make(map[string]struct{})
+		var results []string
+		for _, v := range Transliterate(seed) {
+			for _, name := range e.Generate(v) {
+				if _, ok := seen[name]; !ok {
+					seen[name] = struct{}{}
+					results = append(results, name)
+				}
+			}
+		}
+		return results
+	}
+
 	seen := make(map[string]struct{})
 	var results []string
 
@@ -117,12 +141,42 @@ func (e *Engine) Generate(seed string) []string {
 		}
 	}
 
+	// Custom naming templates
+	if len(e.Templates) > 0 {
+		dicts := e.TemplateDictionaries(seed)
+		for _, t := range e.Templates {
+			for _, name := range t.Generate(dicts, e.Separators, e.TemplateLimit) {
+				add(name)
+			}
+		}
+	}
+
+	// Human variations of the seed, with the same affixes as the seed
+	for _, variant := range e.Mutate(seed) {
+		add(variant)
+		for _, prefix := range e.Prefixes {
+			add(prefix + variant)
+		}
+		for _, suffix := range e.Suffixes {
+			add(variant + suffix)
+		}
+		for _, prefix := range e.Prefixes {
+			for _, suffix := range e.Suffixes {
+				add(prefix + variant + suffix)
+			}
+		}
+	}
+
 	return results
 }
 
 // GenerateFromWordlist applies permutations to each word in the list.
 func (e *Engine) GenerateFromWordlist(words []string, seed string) []string {
 	seed = strings.ToLower(strings.TrimSpace(seed))
+	if v := Transliterate(seed); len(v) > 0 {
+		seed = v[0]
+	}
+	words = TransliterateAll(words)
 	seen := make(map[string]struct{})
 	var results []string
 
@@ -0,0 +1,124 @@
+package permutation
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tokenize splits a bucket name into its lowercase words, dropping
+// single-character fragments.
+func Tokenize(name string) []string {
+	parts := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '.'
+	})
+
+	var tokens []string
+	for _, p := range parts {
+		if len(p) > 1 && !slices.Contains(tokens, p) {
+			tokens = append(tokens, p)
+		}
+	}
+	return tokens
+}
+
+// ExpandFound generates follow-up candidates from buckets that were found.
+// A found name reveals both vocabulary and structure, so each word that
+// belongs to a dictionary is swapped for its siblings (acme-prod-logs ->
+// acme-staging-logs, acme-prod-backups) and the name as a whole is
+// permuted like a seed. At most limit names are returned (0 = unlimited).
+func (e *Engine) ExpandFound(found []string, limit int) []string {
+	seen := make(map[string]struct{})
+	for _, name := range found {
+		seen[strings.ToLower(name)] = struct{}{}
+	}
+
+	var results []string
+	full := func() bool {
+		return limit > 0 && len(results) >= limit
+	}
+	add := func(name string) {
+		if full() {
+			return
+		}
+		if _, ok := seen[name]; !ok && IsValidBucketName(name) {
+			seen[name] = struct{}{}
+			results = append(results, name)
+		}
+	}
+
+	dicts := e.TemplateDictionaries("")
+	classes := []string{"env", "purpose", "prefix", "year"}
+
+	// Sibling substitution, the most targeted guesses
+	for _, name := range found {
+		name = strings.ToLower(name)
+		words, seps := splitName(name)
+
+		for i, word := range words {
+			for _, class := range classes {
+				values := dicts[class]
+				if !slices.Contains(values, word) {
+					continue
+				}
+				for _, v := range values {
+					if v != word {
+						add(joinName(words, seps, i, v))
+					}
+				}
+			}
+		}
+
+		// Regions span several words, so swap them as substrings
+		for _, region := range dicts["region"] {
+			if !strings.Contains(name, region) {
+				continue
+			}
+			for _, other := range dicts["region"] {
+				if other != region {
+					add(strings.Replace(name, region, other, 1))
+				}
+			}
+		}
+	}
+
+	// Found names as seeds
+	for _, name := range found {
+		if full() {
+			break
+		}
+		for _, candidate := range e.Generate(name) {
+			add(candidate)
+		}
+	}
+
+	return results
+}
+
+// splitName splits a name into words and the separators between them.
+func splitName(name string) (words, seps []string) {
+	start := 0
+	for i := 0; i < len(name); i++ {
+		if name[i] == '-' || name[i] == '.' {
+			words = append(words, name[start:i])
+			seps = append(seps, name[i:i+1])
+			start = i + 1
+		}
+	}
+	words = append(words, name[start:])
+	return words, seps
+}
+
+// joinName rebuilds a split name with words[i] replaced by word.
+func joinName(words, seps []string, i int, word string) string {
+	var b strings.Builder
+	for j, w := range words {
+		if j > 0 {
+			b.WriteString(seps[j-1])
+		}
+		if j == i {
+			w = word
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Acme-prod.logs-a-prod")
+	want := []string{"acme", "prod", "logs"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestExpandFound(t *testing.T) {
+	e := Default()
+	names := e.ExpandFound([]string{"acme-prod-logs", "acme-data-us-east-1"}, 0)
+
+	for _, want := range []string{
+		"acme-staging-logs",   // env sibling
+		"acme-prod-backups",   // purpose sibling
+		"acme-data-eu-west-1", // region sibling
+		"acme-prod-logs-dev",  // found name as seed
+		"dev-acme-data-us-east-1",
+	} {
+		if !slices.Contains(names, want) {
+			t.Errorf("ExpandFound() missing %q", want)
+		}
+	}
+
+	for _, found := range []string{"acme-prod-logs", "acme-data-us-east-1"} {
+		if slices.Contains(names, found) {
+			t.Errorf("ExpandFound() repeats found bucket %q", found)
+		}
+	}
+}
+
+func TestExpandFound_SiblingsFirstAndLimit(t *testing.T) {
+	e := Default()
+	names := e.ExpandFound([]string{"acme-prod-logs"}, 5)
+
+	if len(names) != 5 {
+		t.Fatalf("ExpandFound() returned %d names, want 5", len(names))
+	}
+	if names[0] != "acme-dev-logs" {
+		t.Errorf("ExpandFound()[0] = %q, want sibling acme-dev-logs", names[0])
+	}
+}
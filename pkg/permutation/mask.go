@@ -0,0 +1,196 @@
+package permutation
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Built-in mask charsets. Only characters valid in bucket names are offered,
+// so the uppercase and symbol sets of hashcat have no equivalent here.
+var maskCharsets = map[byte]string{
+	'l': "abcdefghijklmnopqrstuvwxyz",
+	'd': "0123456789",
+	'h': "0123456789abcdef",
+	'a': "abcdefghijklmnopqrstuvwxyz0123456789",
+	's': "-.",
+}
+
+// MaskOptions configures a mask.
+type MaskOptions struct {
+	// Custom holds the custom charsets ?1 to ?4. They may reference built-in
+	// charsets, e.g. "?l?d" or "abc?d".
+	Custom [4]string
+	// Increment also generates the leading 1..n-1 positions of the mask,
+	// starting at IncrementMin positions (default 1).
+	Increment    bool
+	IncrementMin int
+}
+
+// Mask is a compiled hashcat-style mask such as "acme-?l?l?d".
+//
+// ?l is a-z, ?d is 0-9, ?h is 0-9a-f, ?a is a-z0-9, ?s is "-." and ?1..?4 are
+// custom charsets. ?? is a literal "?". Any other character is literal.
+type Mask struct {
+	pattern   string
+	positions []string // Characters allowed at each position
+	minLen    int
+}
+
+// CompileMask parses a mask pattern.
+func CompileMask(pattern string, opts MaskOptions) (*Mask, error) {
+	var custom [4]string
+	for i, cs := range opts.Custom {
+		if cs == "" {
+			continue
+		}
+		expanded, err := expandCharset(cs)
+		if err != nil {
+			return nil, fmt.Errorf("custom charset %d: %w", i+1, err)
+		}
+		custom[i] = expanded
+	}
+
+	m := &Mask{pattern: pattern}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != '?' {
+			m.positions = append(m.positions, strings.ToLower(string(c)))
+			continue
+		}
+
+		i++
+		if i >= len(pattern) {
+			return nil, fmt.Errorf("mask %q: trailing '?'", pattern)
+		}
+
+		switch k := pattern[i]; {
+		case k == '?':
+			m.positions = append(m.positions, "?")
+		case k >= '1' && k <= '4':
+			cs := custom[k-'1']
+			if cs == "" {
+				return nil, fmt.Errorf("mask %q: custom charset ?%c is not defined", pattern, k)
+			}
+			m.positions = append(m.positions, cs)
+		default:
+			cs, ok := maskCharsets[k]
+			if !ok {
+				return nil, fmt.Errorf("mask %q: unknown charset ?%c", pattern, k)
+			}
+			m.positions = append(m.positions, cs)
+		}
+	}
+
+	if len(m.positions) == 0 {
+		return nil, fmt.Errorf("mask is empty")
+	}
+
+	m.minLen = len(m.positions)
+	if opts.Increment {
+		m.minLen = max(opts.IncrementMin, 1)
+		if m.minLen > len(m.positions) {
+			return nil, fmt.Errorf("mask %q: increment minimum %d exceeds mask length %d", pattern, m.minLen, len(m.positions))
+		}
+	}
+
+	return m, nil
+}
+
+// expandCharset resolves built-in charset references inside a custom charset
+// and removes duplicates.
+func expandCharset(cs string) (string, error) {
+	var b strings.Builder
+	seen := make(map[byte]bool)
+	add := func(s string) {
+		for i := 0; i < len(s); i++ {
+			if !seen[s[i]] {
+				seen[s[i]] = true
+				b.WriteByte(s[i])
+			}
+		}
+	}
+
+	cs = strings.ToLower(cs)
+	for i := 0; i < len(cs); i++ {
+		if cs[i] != '?' {
+			add(cs[i : i+1])
+			continue
+		}
+		i++
+		if i >= len(cs) {
+			return "", fmt.Errorf("trailing '?'")
+		}
+		if cs[i] == '?' {
+			add("?")
+			continue
+		}
+		builtin, ok := maskCharsets[cs[i]]
+		if !ok {
+			return "", fmt.Errorf("unknown charset ?%c", cs[i])
+		}
+		add(builtin)
+	}
+
+	return b.String(), nil
+}
+
+// String returns the source pattern.
+func (m *Mask) String() string {
+	return m.pattern
+}
+
+// Keyspace returns the number of candidates the mask enumerates, before
+// filtering invalid bucket names.
+func (m *Mask) Keyspace() *big.Int {
+	total := new(big.Int)
+	for n := m.minLen; n <= len(m.positions); n++ {
+		size := big.NewInt(1)
+		for _, cs := range m.positions[:n] {
+			size.Mul(size, big.NewInt(int64(len(cs))))
+		}
+		total.Add(total, size)
+	}
+	return total
+}
+
+// Each calls fn for every valid bucket name the mask produces, shortest
+// lengths first. Names are generated lazily; enumeration stops when fn
+// returns false.
+func (m *Mask) Each(fn func(string) bool) {
+	for n := m.minLen; n <= len(m.positions); n++ {
+		if !m.each(m.positions[:n], fn) {
+			return
+		}
+	}
+}
+
+// each enumerates one fixed-length mask. It returns false if fn stopped it.
+func (m *Mask) each(positions []string, fn func(string) bool) bool {
+	idx := make([]int, len(positions))
+	buf := make([]byte, len(positions))
+
+	for {
+		for i, cs := range positions {
+			buf[i] = cs[idx[i]]
+		}
+		if name := string(buf); IsValidBucketName(name) {
+			if !fn(name) {
+				return false
+			}
+		}
+
+		// Odometer increment, rightmost position fastest
+		i := len(idx) - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < len(positions[i]) {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return true
+		}
+	}
+}
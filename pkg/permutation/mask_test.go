@@ -0,0 +1,115 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+)
+
+func collectMask(m *Mask) []string {
+	var names []string
+	m.Each(func(name string) bool {
+		names = append(names, name)
+		return true
+	})
+	return names
+}
+
+func TestCompileMask_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    MaskOptions
+	}{
+		{"empty", "", MaskOptions{}},
+		{"trailing question mark", "acme-?", MaskOptions{}},
+		{"unknown charset", "acme-?u", MaskOptions{}},
+		{"undefined custom charset", "acme-?1", MaskOptions{}},
+		{"bad custom charset", "acme-?1", MaskOptions{Custom: [4]string{"?x"}}},
+		{"increment min too large", "acme?d", MaskOptions{Increment: true, IncrementMin: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileMask(tt.pattern, tt.opts); err == nil {
+				t.Errorf("CompileMask(%q) expected error", tt.pattern)
+			}
+		})
+	}
+}
+
+func TestMask_Each(t *testing.T) {
+	m, err := CompileMask("acme-db?d?d", MaskOptions{})
+	if err != nil {
+		t.Fatalf("CompileMask() error = %v", err)
+	}
+
+	names := collectMask(m)
+	if len(names) != 100 {
+		t.Fatalf("Each() produced %d names, want 100", len(names))
+	}
+	if names[0] != "acme-db00" || names[99] != "acme-db99" {
+		t.Errorf("Each() first/last = %q/%q", names[0], names[99])
+	}
+	if got := m.Keyspace().Int64(); got != 100 {
+		t.Errorf("Keyspace() = %d, want 100", got)
+	}
+}
+
+func TestMask_CustomCharsetAndLiterals(t *testing.T) {
+	m, err := CompileMask("ACME?1??", MaskOptions{Custom: [4]string{"xy?d"}})
+	if err != nil {
+		t.Fatalf("CompileMask() error = %v", err)
+	}
+	if got := m.Keyspace().Int64(); got != 12 {
+		t.Errorf("Keyspace() = %d, want 12", got)
+	}
+
+	// "?" is never valid in a bucket name, so everything is filtered
+	if names := collectMask(m); len(names) != 0 {
+		t.Errorf("Each() = %v, want none", names)
+	}
+}
+
+func TestMask_FiltersInvalidNames(t *testing.T) {
+	m, err := CompileMask("ac?s?s", MaskOptions{})
+	if err != nil {
+		t.Fatalf("CompileMask() error = %v", err)
+	}
+
+	// Every candidate ends in a separator, which bucket names forbid
+	if names := collectMask(m); len(names) != 0 {
+		t.Errorf("Each() = %v, want none", names)
+	}
+}
+
+func TestMask_Increment(t *testing.T) {
+	m, err := CompileMask("ab?d?d", MaskOptions{Increment: true, IncrementMin: 3})
+	if err != nil {
+		t.Fatalf("CompileMask() error = %v", err)
+	}
+
+	if got := m.Keyspace().Int64(); got != 110 {
+		t.Errorf("Keyspace() = %d, want 110", got)
+	}
+
+	names := collectMask(m)
+	if len(names) != 110 || names[0] != "ab0" || !slices.Contains(names, "ab42") {
+		t.Errorf("Each() produced %d names starting with %q", len(names), names[0])
+	}
+}
+
+func TestMask_EachStops(t *testing.T) {
+	m, err := CompileMask("acme?a?a?a?a?a?a", MaskOptions{})
+	if err != nil {
+		t.Fatalf("CompileMask() error = %v", err)
+	}
+
+	count := 0
+	m.Each(func(string) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Errorf("Each() visited %d names after stop, want 10", count)
+	}
+}
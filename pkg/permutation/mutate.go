@@ -0,0 +1,272 @@
+package permutation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mutator applies one kind of human variation to the words of a name.
+type Mutator int
+
+const (
+	MutateAbbrev Mutator = iota // production <-> prod, development <-> dev
+	MutatePlural                // backup <-> backups, policy <-> policies
+	MutateVowels                // backup -> bckp
+	MutateLeet                  // acme -> 4cme, acm3, 4cm3
+	MutateTypo                  // acme -> acm, came, accme, acne
+)
+
+var mutatorNames = map[Mutator]string{
+	MutateAbbrev: "abbrev",
+	MutatePlural: "plural",
+	MutateVowels: "vowels",
+	MutateLeet:   "leet",
+	MutateTypo:   "typo",
+}
+
+func (m Mutator) String() string {
+	if name, ok := mutatorNames[m]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseMutators parses mutator names such as "abbrev" or "leet". "all"
+// selects every mutator.
+func ParseMutators(names []string) ([]Mutator, error) {
+	var mutators []Mutator
+	seen := make(map[Mutator]bool)
+	add := func(m Mutator) {
+		if !seen[m] {
+			seen[m] = true
+			mutators = append(mutators, m)
+		}
+	}
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			for m := MutateAbbrev; m <= MutateTypo; m++ {
+				add(m)
+			}
+			continue
+		}
+		found := false
+		for m, n := range mutatorNames {
+			if n == name {
+				add(m)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown mutator %q (valid: abbrev, plural, vowels, leet, typo, all)", name)
+		}
+	}
+	return mutators, nil
+}
+
+// abbreviations maps long words to their common short forms.
+var abbreviations = map[string][]string{
+	"production":     {"prod", "prd"},
+	"development":    {"dev"},
+	"staging":        {"stg", "stage"},
+	"environment":    {"env"},
+	"application":    {"app"},
+	"applications":   {"apps"},
+	"database":       {"db"},
+	"configuration":  {"config", "cfg"},
+	"documents":      {"docs"},
+	"repository":     {"repo"},
+	"administration": {"admin"},
+	"management":     {"mgmt"},
+	"marketing":      {"mktg"},
+	"international":  {"intl"},
+	"corporation":    {"corp"},
+	"technology":     {"tech"},
+	"information":    {"info"},
+	"engineering":    {"eng"},
+	"infrastructure": {"infra"},
+	"operations":     {"ops"},
+	"security":       {"sec"},
+	"service":        {"svc"},
+	"services":       {"svc"},
+	"temporary":      {"tmp", "temp"},
+	"images":         {"img"},
+	"library":        {"lib"},
+	"backup":         {"bkp", "bak"},
+	"message":        {"msg"},
+	"customer":       {"cust"},
+	"finance":        {"fin"},
+	"statistics":     {"stats"},
+	"testing":        {"test"},
+	"quality":        {"qa"},
+	"europe":         {"eu"},
+}
+
+// expansions is the reverse of abbreviations.
+var expansions = func() map[string][]string {
+	m := make(map[string][]string)
+	for long, shorts := range abbreviations {
+		for _, short := range shorts {
+			m[short] = append(m[short], long)
+		}
+	}
+	return m
+}()
+
+var leet = map[byte]byte{'a': '4', 'e': '3', 'i': '1', 'o': '0', 's': '5', 't': '7'}
+
+// keyboardNeighbors lists the adjacent keys of each letter on a QWERTY
+// keyboard, the most common source of substitution typos.
+var keyboardNeighbors = map[byte]string{
+	'a': "qsz", 'b': "vn", 'c': "xv", 'd': "sf", 'e': "wr", 'f': "dg", 'g': "fh",
+	'h': "gj", 'i': "uo", 'j': "hk", 'k': "jl", 'l': "k", 'm': "n", 'n': "bm",
+	'o': "ip", 'p': "o", 'q': "wa", 'r': "et", 's': "ad", 't': "ry", 'u': "yi",
+	'v': "cb", 'w': "qe", 'x': "zc", 'y': "tu", 'z': "x",
+}
+
+// Mutate returns variations of name produced by the engine's mutators,
+// applied to one word at a time (acme-production -> acme-prod). Mutators run
+// in order and at most MutateLimit names are returned (0 = unlimited).
+func (e *Engine) Mutate(name string) []string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || len(e.Mutators) == 0 {
+		return nil
+	}
+
+	seen := map[string]struct{}{name: {}}
+	var results []string
+	full := func() bool {
+		return e.MutateLimit > 0 && len(results) >= e.MutateLimit
+	}
+
+	words, seps := splitName(name)
+	for _, m := range e.Mutators {
+		for i, word := range words {
+			for _, variant := range mutateWord(m, word) {
+				if full() {
+					return results
+				}
+				candidate := joinName(words, seps, i, variant)
+				if _, ok := seen[candidate]; !ok && IsValidBucketName(candidate) {
+					seen[candidate] = struct{}{}
+					results = append(results, candidate)
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// mutateWord returns the variations of a single word.
+func mutateWord(m Mutator, word string) []string {
+	if word == "" || strings.Trim(word, "0123456789") == "" {
+		return nil
+	}
+
+	switch m {
+	case MutateAbbrev:
+		return append(append([]string(nil), abbreviations[word]...), expansions[word]...)
+	case MutatePlural:
+		return pluralVariants(word)
+	case MutateVowels:
+		return dropVowels(word)
+	case MutateLeet:
+		return leetVariants(word)
+	case MutateTypo:
+		return typos(word)
+	}
+	return nil
+}
+
+func pluralVariants(word string) []string {
+	if len(word) < 3 {
+		return nil
+	}
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		return []string{word[:len(word)-3] + "y"}
+	case strings.HasSuffix(word, "ses"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return []string{word[:len(word)-2]}
+	case strings.HasSuffix(word, "ss"):
+		return []string{word + "es"}
+	case strings.HasSuffix(word, "s"):
+		return []string{word[:len(word)-1]}
+	case strings.HasSuffix(word, "y") && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return []string{word[:len(word)-1] + "ies"}
+	case strings.HasSuffix(word, "x"), strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return []string{word + "es"}
+	default:
+		return []string{word + "s"}
+	}
+}
+
+// dropVowels removes the vowels after the first letter (backup -> bckp).
+func dropVowels(word string) []string {
+	if len(word) < 4 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteByte(word[0])
+	for i := 1; i < len(word); i++ {
+		if !strings.ContainsRune("aeiou", rune(word[i])) {
+			b.WriteByte(word[i])
+		}
+	}
+	if short := b.String(); short != word && len(short) >= 2 {
+		return []string{short}
+	}
+	return nil
+}
+
+// leetVariants substitutes each leet letter on its own, then all at once.
+func leetVariants(word string) []string {
+	var variants []string
+	all := []byte(word)
+	for _, c := range []byte("aeiost") {
+		if !strings.ContainsRune(word, rune(c)) {
+			continue
+		}
+		variants = append(variants, strings.ReplaceAll(word, string(c), string(leet[c])))
+		for i := range all {
+			if all[i] == c {
+				all[i] = leet[c]
+			}
+		}
+	}
+	if len(variants) > 1 {
+		variants = append(variants, string(all))
+	}
+	return variants
+}
+
+// typos returns single-character deletions, transpositions, doublings and
+// keyboard-neighbor substitutions.
+func typos(word string) []string {
+	if len(word) < 3 {
+		return nil
+	}
+	var variants []string
+	for i := 0; i < len(word); i++ {
+		variants = append(variants, word[:i]+word[i+1:])
+	}
+	for i := 0; i+1 < len(word); i++ {
+		if word[i] != word[i+1] {
+			variants = append(variants, word[:i]+word[i+1:i+2]+word[i:i+1]+word[i+2:])
+		}
+	}
+	for i := 0; i < len(word); i++ {
+		variants = append(variants, word[:i+1]+word[i:])
+	}
+	for i := 0; i < len(word); i++ {
+		for _, n := range []byte(keyboardNeighbors[word[i]]) {
+			variants = append(variants, word[:i]+string(n)+word[i+1:])
+		}
+	}
+	return variants
+}
@@ -0,0 +1,116 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMutators(t *testing.T) {
+	got, err := ParseMutators([]string{"Leet", " typo", "leet", ""})
+	if err != nil {
+		t.Fatalf("ParseMutators() error = %v", err)
+	}
+	if want := []Mutator{MutateLeet, MutateTypo}; !slices.Equal(got, want) {
+		t.Errorf("ParseMutators() = %v, want %v", got, want)
+	}
+
+	all, err := ParseMutators([]string{"all"})
+	if err != nil || len(all) != 5 {
+		t.Errorf("ParseMutators(all) = %v, %v, want 5 mutators", all, err)
+	}
+
+	if _, err := ParseMutators([]string{"rot13"}); err == nil {
+		t.Error("ParseMutators(rot13) error = nil, want error")
+	}
+}
+
+func TestMutateWord(t *testing.T) {
+	tests := []struct {
+		mutator  Mutator
+		word     string
+		expected []string
+	}{
+		{MutateAbbrev, "production", []string{"prod", "prd"}},
+		{MutateAbbrev, "dev", []string{"development"}},
+		{MutateAbbrev, "acme", nil},
+		{MutatePlural, "backup", []string{"backups"}},
+		{MutatePlural, "backups", []string{"backup"}},
+		{MutatePlural, "policy", []string{"policies"}},
+		{MutatePlural, "policies", []string{"policy"}},
+		{MutatePlural, "boxes", []string{"box"}},
+		{MutatePlural, "box", []string{"boxes"}},
+		{MutatePlural, "access", []string{"accesses"}},
+		{MutateVowels, "backup", []string{"bckp"}},
+		{MutateVowels, "acme", []string{"acm"}},
+		{MutateVowels, "app", nil},
+		{MutateLeet, "acme", []string{"4cme", "acm3", "4cm3"}},
+		{MutateLeet, "cdn", nil},
+		{MutateTypo, "ab", nil},
+		{MutatePlural, "2024", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mutator.String()+"/"+tt.word, func(t *testing.T) {
+			if got := mutateWord(tt.mutator, tt.word); !slices.Equal(got, tt.expected) {
+				t.Errorf("mutateWord(%s, %q) = %v, want %v", tt.mutator, tt.word, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTypos(t *testing.T) {
+	got := typos("acme")
+	for _, want := range []string{"cme", "acm", "came", "acem", "aacme", "acmee", "acne", "scme"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("typos(acme) missing %q", want)
+		}
+	}
+}
+
+func TestEngine_Mutate(t *testing.T) {
+	e := Default()
+	e.Mutators = []Mutator{MutateAbbrev, MutatePlural}
+
+	got := e.Mutate("acme-production-backup")
+	for _, want := range []string{"acme-prod-backup", "acme-production-bkp", "acme-production-backups", "acmes-production-backup"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("Mutate() missing %q, got %v", want, got)
+		}
+	}
+	if slices.Contains(got, "acme-production-backup") {
+		t.Error("Mutate() returned the input name")
+	}
+}
+
+func TestEngine_Mutate_Limit(t *testing.T) {
+	e := Default()
+	e.Mutators = []Mutator{MutateTypo}
+	e.MutateLimit = 5
+
+	if got := e.Mutate("acme-backups"); len(got) != 5 {
+		t.Errorf("Mutate() returned %d names, want 5", len(got))
+	}
+
+	e.Mutators = nil
+	if got := e.Mutate("acme"); got != nil {
+		t.Errorf("Mutate() without mutators = %v, want nil", got)
+	}
+}
+
+func TestEngine_Generate_Mutators(t *testing.T) {
+	e := Default()
+	if names := e.Generate("acme"); slices.Contains(names, "4cme") {
+		t.Error("Generate() applied leet without mutators")
+	}
+
+	e.Mutators = []Mutator{MutateLeet}
+	names := e.Generate("acme")
+	for _, want := range []string{"4cme", "dev-4cme", "4cme-backup", "dev-4cme-backup"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Generate() missing leet variant %s", want)
+		}
+	}
+	if got := int64(len(names)); got > e.Estimate() {
+		t.Errorf("Generate() = %d names, Estimate() = %d", got, e.Estimate())
+	}
+}
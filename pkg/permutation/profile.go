@@ -0,0 +1,291 @@
+package permutation
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed profiles/*.yaml
+var builtinProfiles embed.FS
+
+// DefaultProfile is the profile equivalent to Default().
+const DefaultProfile = "default"
+
+// Profile is a named set of permutation dictionaries.
+//
+// Lists can be given inline or, in YAML, loaded from a TXT wordlist next to
+// the profile via the matching *_file key (suffixes_file: suffixes.txt).
+type Profile struct {
+	Name         string              `yaml:"name"`
+	Description  string              `yaml:"description"`
+	Prefixes     []string            `yaml:"prefixes"`
+	Suffixes     []string            `yaml:"suffixes"`
+	Separators   []string            `yaml:"separators"`
+	Years        []string            `yaml:"years"`
+	Regions      []string            `yaml:"regions"`
+	Dictionaries map[string][]string `yaml:"dictionaries"`
+	Templates    []string            `yaml:"templates"`
+
+	// DateFormats selects the generated date tokens (see DateFormats).
+	// Years lists extra fixed tokens on top of the generated ones.
+	DateFormats []string   `yaml:"date_formats"`
+	FiscalStart time.Month `yaml:"fiscal_start"`
+
+	PrefixesFile string `yaml:"prefixes_file"`
+	SuffixesFile string `yaml:"suffixes_file"`
+	YearsFile    string `yaml:"years_file"`
+	RegionsFile  string `yaml:"regions_file"`
+}
+
+// ProfileNames returns the names of the built-in profiles.
+func ProfileNames() []string {
+	entries, err := builtinProfiles.ReadDir("profiles")
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadProfile returns a built-in profile by name, or loads a profile from a
+// .yaml/.yml or .txt file path.
+func LoadProfile(nameOrPath string) (*Profile, error) {
+	if nameOrPath == "" {
+		nameOrPath = DefaultProfile
+	}
+
+	if data, err := builtinProfiles.ReadFile("profiles/" + nameOrPath + ".yaml"); err == nil {
+		return parseYAMLProfile(data, "")
+	}
+
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		if os.IsNotExist(err) && filepath.Ext(nameOrPath) == "" {
+			return nil, fmt.Errorf("unknown profile %q (available: %s)", nameOrPath, strings.Join(ProfileNames(), ", "))
+		}
+		return nil, err
+	}
+
+	var p *Profile
+	switch strings.ToLower(filepath.Ext(nameOrPath)) {
+	case ".yaml", ".yml":
+		p, err = parseYAMLProfile(data, filepath.Dir(nameOrPath))
+	case ".txt":
+		p, err = parseTXTProfile(data)
+	default:
+		return nil, fmt.Errorf("unsupported profile format: %s", nameOrPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", nameOrPath, err)
+	}
+
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(nameOrPath), filepath.Ext(nameOrPath))
+	}
+	return p, nil
+}
+
+// parseYAMLProfile decodes a YAML profile and resolves *_file references
+// relative to dir.
+func parseYAMLProfile(data []byte, dir string) (*Profile, error) {
+	var p Profile
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		path string
+		dst  *[]string
+	}{
+		{p.PrefixesFile, &p.Prefixes},
+		{p.SuffixesFile, &p.Suffixes},
+		{p.YearsFile, &p.Years},
+		{p.RegionsFile, &p.Regions},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		path := f.path
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+		words, err := readLines(path)
+		if err != nil {
+			return nil, err
+		}
+		*f.dst = append(*f.dst, words...)
+	}
+
+	return &p, nil
+}
+
+// parseTXTProfile reads a plain-text profile made of [section] headers
+// followed by one entry per line. Sections are prefixes, suffixes,
+// separators, years, regions and templates; any other section becomes a
+// template dictionary. Lines starting with # are comments.
+func parseTXTProfile(data []byte) (*Profile, error) {
+	p := &Profile{}
+	var section string
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+
+		switch section {
+		case "":
+			return nil, fmt.Errorf("line %d: entry outside of a [section]", lineNo)
+		case "prefixes":
+			p.Prefixes = append(p.Prefixes, line)
+		case "suffixes":
+			p.Suffixes = append(p.Suffixes, line)
+		case "separators":
+			p.Separators = append(p.Separators, line)
+		case "years":
+			p.Years = append(p.Years, line)
+		case "regions":
+			p.Regions = append(p.Regions, line)
+		case "templates":
+			p.Templates = append(p.Templates, line)
+		default:
+			if p.Dictionaries == nil {
+				p.Dictionaries = make(map[string][]string)
+			}
+			p.Dictionaries[section] = append(p.Dictionaries[section], line)
+		}
+	}
+
+	return p, sc.Err()
+}
+
+// readLines returns the non-empty, non-comment lines of a file, with
+// non-ASCII words transliterated.
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return TransliterateAll(lines), nil
+}
+
+// DateRange returns the default date range with the profile's formats.
+func (p *Profile) DateRange() DateRange {
+	d := DefaultDateRange()
+	d.Formats = p.DateFormats
+	d.FiscalStart = p.FiscalStart
+	return d
+}
+
+// Engine builds a permutation engine from the profile.
+func (p *Profile) Engine() (*Engine, error) {
+	return p.EngineWithDates(p.DateRange())
+}
+
+// EngineWithDates builds a permutation engine whose years come from dates.
+func (p *Profile) EngineWithDates(dates DateRange) (*Engine, error) {
+	if err := dates.Validate(); err != nil {
+		return nil, err
+	}
+
+	years := dates.Suffixes()
+	for _, y := range p.Years {
+		if y != "" && !slices.Contains(years, y) {
+			years = append(years, y)
+		}
+	}
+
+	e := &Engine{
+		Prefixes:      withEmpty(p.Prefixes),
+		Suffixes:      withEmpty(p.Suffixes),
+		Separators:    p.Separators,
+		Years:         years,
+		Regions:       withEmpty(p.Regions),
+		Dictionaries:  p.Dictionaries,
+		TemplateLimit: Default().TemplateLimit,
+		MutateLimit:   Default().MutateLimit,
+	}
+	if len(e.Separators) == 0 {
+		e.Separators = []string{"-"}
+	}
+
+	for _, pattern := range p.Templates {
+		if err := e.AddTemplate(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// withEmpty ensures the list starts with "" so that combination loops also
+// produce names without the affix.
+func withEmpty(affixes []string) []string {
+	out := []string{""}
+	for _, a := range affixes {
+		if a != "" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Estimate returns the number of candidate names Generate tries per seed,
+// before deduplication and validation. It is an upper bound on the output.
+func (e *Engine) Estimate() int64 {
+	p, s := int64(len(e.Prefixes)), int64(len(e.Suffixes))
+	y, r := int64(len(e.Years)), int64(len(e.Regions))
+
+	n := 1 + p + s + p*s + y + s*y + r + s*r
+	for _, sep := range e.Separators {
+		if sep != "-" {
+			n += 1 + s
+		}
+	}
+
+	if len(e.Templates) > 0 {
+		dicts := e.TemplateDictionaries("seed")
+		for _, t := range e.Templates {
+			c := t.Count(dicts, e.Separators)
+			if e.TemplateLimit > 0 && c > int64(e.TemplateLimit) {
+				c = int64(e.TemplateLimit)
+			}
+			n += c
+		}
+	}
+
+	if len(e.Mutators) > 0 {
+		n += int64(e.MutateLimit) * (1 + p + s + p*s)
+	}
+
+	return n
+}
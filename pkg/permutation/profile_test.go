@@ -0,0 +1,135 @@
+package permutation
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadProfile_DefaultMatchesDefault(t *testing.T) {
+	p, err := LoadProfile(DefaultProfile)
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	e, err := p.Engine()
+	if err != nil {
+		t.Fatalf("Engine() error = %v", err)
+	}
+
+	want := Default()
+	if !slices.Equal(e.Prefixes, want.Prefixes) || !slices.Equal(e.Suffixes, want.Suffixes) ||
+		!slices.Equal(e.Years, want.Years) || !slices.Equal(e.Regions, want.Regions) ||
+		!slices.Equal(e.Separators, want.Separators) {
+		t.Error("default profile differs from Default()")
+	}
+}
+
+func TestLoadProfile_Builtins(t *testing.T) {
+	names := ProfileNames()
+	for _, want := range []string{"minimal", "default", "aggressive", "industry-fintech", "industry-health"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("ProfileNames() missing %q", want)
+		}
+	}
+
+	estimates := make(map[string]int64)
+	for _, name := range names {
+		p, err := LoadProfile(name)
+		if err != nil {
+			t.Fatalf("LoadProfile(%q) error = %v", name, err)
+		}
+		e, err := p.Engine()
+		if err != nil {
+			t.Fatalf("%s: Engine() error = %v", name, err)
+		}
+		got := len(e.Generate("acme"))
+		if got == 0 || int64(got) > e.Estimate() {
+			t.Errorf("%s: Generate() = %d names, Estimate() = %d", name, got, e.Estimate())
+		}
+		estimates[name] = e.Estimate()
+	}
+
+	if estimates["minimal"] >= estimates["default"] || estimates["default"] >= estimates["aggressive"] {
+		t.Errorf("expected minimal < default < aggressive, got %v", estimates)
+	}
+}
+
+func TestLoadProfile_Unknown(t *testing.T) {
+	if _, err := LoadProfile("nonexistent"); err == nil {
+		t.Error("LoadProfile() expected error for unknown profile")
+	}
+}
+
+func TestLoadProfile_YAMLFile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "suffixes.txt"), []byte("# comment\n-vault\n\n-ledger\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "client.yaml"), []byte(`
+suffixes: [-core]
+suffixes_file: suffixes.txt
+dictionaries:
+  team: [payments]
+templates:
+  - "{team}{sep}{seed}"
+`), 0644)
+
+	p, err := LoadProfile(filepath.Join(dir, "client.yaml"))
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if p.Name != "client" {
+		t.Errorf("Name = %q, want client", p.Name)
+	}
+	if !slices.Equal(p.Suffixes, []string{"-core", "-vault", "-ledger"}) {
+		t.Errorf("Suffixes = %v", p.Suffixes)
+	}
+
+	e, err := p.Engine()
+	if err != nil {
+		t.Fatalf("Engine() error = %v", err)
+	}
+	names := e.Generate("acme")
+	for _, want := range []string{"acme", "acme-vault", "payments-acme"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Generate() missing %q", want)
+		}
+	}
+}
+
+func TestLoadProfile_TXTFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client.txt")
+	os.WriteFile(path, []byte(`# client conventions
+[suffixes]
+-raw
+-curated
+[env]
+prd
+[templates]
+{env}{sep}{seed}
+`), 0644)
+
+	p, err := LoadProfile(path)
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if !slices.Equal(p.Suffixes, []string{"-raw", "-curated"}) {
+		t.Errorf("Suffixes = %v", p.Suffixes)
+	}
+	if !slices.Equal(p.Dictionaries["env"], []string{"prd"}) {
+		t.Errorf("Dictionaries[env] = %v", p.Dictionaries["env"])
+	}
+
+	e, err := p.Engine()
+	if err != nil {
+		t.Fatalf("Engine() error = %v", err)
+	}
+	if !slices.Contains(e.Generate("acme"), "prd-acme") {
+		t.Error("Generate() missing templated name prd-acme")
+	}
+}
+
+func TestParseTXTProfile_EntryOutsideSection(t *testing.T) {
+	if _, err := parseTXTProfile([]byte("-dev\n")); err == nil {
+		t.Error("parseTXTProfile() expected error")
+	}
+}
@@ -0,0 +1,319 @@
+package permutation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SeedSlot and SepSlot are the slot names with built-in meaning in templates.
+const (
+	SeedSlot = "seed"
+	SepSlot  = "sep"
+)
+
+// DefaultEnvironments are the values of the built-in {env} dictionary.
+var DefaultEnvironments = []string{
+	"dev", "prod", "production", "staging", "stage", "test", "qa", "uat", "sandbox",
+}
+
+// Template is a compiled naming pattern such as
+// "{env}{sep}{seed}{sep}{purpose?}{sep}{year?}".
+//
+// Slots are written as {name} and draw values from the dictionary of that
+// name. A trailing "?" makes the slot optional: it may also be empty, in which
+// case the neighbouring {sep} collapses. {sep} draws from the separator set
+// and uses the same separator for every {sep} in one name. Anything outside
+// braces is copied literally.
+type Template struct {
+	pattern string
+	tokens  []templateToken
+}
+
+// templateToken is either a literal or a slot.
+type templateToken struct {
+	literal  string
+	slot     string
+	optional bool
+}
+
+// CompileTemplate parses a template pattern.
+func CompileTemplate(pattern string) (*Template, error) {
+	t := &Template{pattern: pattern}
+
+	rest := pattern
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if close := strings.IndexByte(rest, '}'); close >= 0 && (open < 0 || close < open) {
+			return nil, fmt.Errorf("template %q: unexpected '}'", pattern)
+		}
+		if open < 0 {
+			t.tokens = append(t.tokens, templateToken{literal: rest})
+			break
+		}
+		if open > 0 {
+			t.tokens = append(t.tokens, templateToken{literal: rest[:open]})
+		}
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("template %q: unclosed '{'", pattern)
+		}
+		name := rest[open+1 : open+end]
+		rest = rest[open+end+1:]
+
+		tok := templateToken{}
+		if strings.HasSuffix(name, "?") {
+			tok.optional = true
+			name = strings.TrimSuffix(name, "?")
+		}
+		if !isSlotName(name) {
+			return nil, fmt.Errorf("template %q: invalid slot name %q", pattern, name)
+		}
+		if name == SepSlot && tok.optional {
+			return nil, fmt.Errorf("template %q: {sep} cannot be optional", pattern)
+		}
+		tok.slot = name
+		t.tokens = append(t.tokens, tok)
+	}
+
+	if len(t.tokens) == 0 {
+		return nil, fmt.Errorf("template is empty")
+	}
+
+	return t, nil
+}
+
+// MustCompileTemplate is like CompileTemplate but panics on error.
+func MustCompileTemplate(pattern string) *Template {
+	t, err := CompileTemplate(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// String returns the source pattern.
+func (t *Template) String() string {
+	return t.pattern
+}
+
+// Slots returns the distinct dictionary names the template refers to.
+func (t *Template) Slots() []string {
+	seen := make(map[string]struct{})
+	var slots []string
+	for _, tok := range t.tokens {
+		if tok.slot == "" || tok.slot == SepSlot {
+			continue
+		}
+		if _, ok := seen[tok.slot]; !ok {
+			seen[tok.slot] = struct{}{}
+			slots = append(slots, tok.slot)
+		}
+	}
+	return slots
+}
+
+// Validate reports slots that have no dictionary.
+func (t *Template) Validate(dicts map[string][]string) error {
+	for _, slot := range t.Slots() {
+		if _, ok := dicts[slot]; !ok {
+			return fmt.Errorf("template %q: unknown dictionary {%s}", t.pattern, slot)
+		}
+	}
+	return nil
+}
+
+// Count returns the number of raw combinations the template expands to,
+// before deduplication and validation.
+func (t *Template) Count(dicts map[string][]string, separators []string) int64 {
+	count := int64(1)
+	if t.usesSep() {
+		count = int64(max(len(separators), 1))
+	}
+	for _, tok := range t.tokens {
+		if tok.slot == "" || tok.slot == SepSlot {
+			continue
+		}
+		n := int64(len(dicts[tok.slot]))
+		if tok.optional {
+			n++
+		}
+		count *= n
+	}
+	return count
+}
+
+// Expand calls fn for every name the template produces. Names are lowercased
+// but not deduplicated or validated. Expansion stops when fn returns false.
+func (t *Template) Expand(dicts map[string][]string, separators []string, fn func(string) bool) {
+	if !t.usesSep() || len(separators) == 0 {
+		separators = []string{""}
+	}
+
+	// Choices per token; optional slots get an extra empty value
+	choices := make([][]string, len(t.tokens))
+	for i, tok := range t.tokens {
+		switch {
+		case tok.slot == "":
+			choices[i] = []string{tok.literal}
+		case tok.slot == SepSlot:
+			choices[i] = []string{""} // filled per separator
+		default:
+			values := dicts[tok.slot]
+			if tok.optional {
+				values = append([]string{""}, values...)
+			}
+			if len(values) == 0 {
+				return
+			}
+			choices[i] = values
+		}
+	}
+
+	idx := make([]int, len(t.tokens))
+	parts := make([]string, len(t.tokens))
+
+	for _, sep := range separators {
+		for i := range idx {
+			idx[i] = 0
+		}
+		for {
+			for i, tok := range t.tokens {
+				if tok.slot == SepSlot {
+					parts[i] = sep
+				} else {
+					parts[i] = choices[i][idx[i]]
+				}
+			}
+			if !fn(strings.ToLower(t.join(parts, sep))) {
+				return
+			}
+
+			// Odometer increment
+			i := len(idx) - 1
+			for ; i >= 0; i-- {
+				idx[i]++
+				if idx[i] < len(choices[i]) {
+					break
+				}
+				idx[i] = 0
+			}
+			if i < 0 {
+				break
+			}
+		}
+	}
+}
+
+// Generate returns the valid, deduplicated names the template produces, up to limit (0 = unlimited).
+func (t *Template) Generate(dicts map[string][]string, separators []string, limit int) []string {
+	seen := make(map[string]struct{})
+	var results []string
+
+	t.Expand(dicts, separators, func(name string) bool {
+		if _, ok := seen[name]; ok || !IsValidBucketName(name) {
+			return true
+		}
+		seen[name] = struct{}{}
+		results = append(results, name)
+		return limit <= 0 || len(results) < limit
+	})
+
+	return results
+}
+
+// join concatenates rendered tokens, collapsing separators next to empty
+// optional slots and trimming them from the ends.
+func (t *Template) join(parts []string, sep string) string {
+	var b strings.Builder
+	pendingSep := false
+	for i, tok := range t.tokens {
+		if tok.slot == SepSlot {
+			pendingSep = b.Len() > 0
+			continue
+		}
+		if parts[i] == "" {
+			continue
+		}
+		if pendingSep {
+			b.WriteString(sep)
+			pendingSep = false
+		}
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+// usesSep reports whether the template contains a {sep} slot.
+func (t *Template) usesSep() bool {
+	for _, tok := range t.tokens {
+		if tok.slot == SepSlot {
+			return true
+		}
+	}
+	return false
+}
+
+// isSlotName reports whether s is a valid dictionary name.
+func isSlotName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// AddTemplate compiles pattern and appends it to the engine's templates.
+func (e *Engine) AddTemplate(pattern string) error {
+	t, err := CompileTemplate(pattern)
+	if err != nil {
+		return err
+	}
+	if err := t.Validate(e.TemplateDictionaries("x")); err != nil {
+		return err
+	}
+	e.Templates = append(e.Templates, t)
+	return nil
+}
+
+// TemplateDictionaries returns the named dictionaries available to templates,
+// built from the engine's affix lists plus any custom dictionaries. The seed
+// dictionary holds the given seed.
+func (e *Engine) TemplateDictionaries(seed string) map[string][]string {
+	dicts := map[string][]string{
+		"prefix":  trimAffixes(e.Prefixes),
+		"suffix":  trimAffixes(e.Suffixes),
+		"purpose": trimAffixes(e.Suffixes),
+		"year":    trimAffixes(e.Years),
+		"region":  trimAffixes(e.Regions),
+		"env":     DefaultEnvironments,
+	}
+	for name, values := range e.Dictionaries {
+		dicts[name] = values
+	}
+	if seed != "" {
+		dicts[SeedSlot] = []string{seed}
+	}
+	return dicts
+}
+
+// trimAffixes strips leading/trailing separators and drops empty entries.
+func trimAffixes(affixes []string) []string {
+	seen := make(map[string]struct{})
+	var out []string
+	for _, a := range affixes {
+		a = strings.Trim(a, "-.")
+		if a == "" {
+			continue
+		}
+		if _, ok := seen[a]; !ok {
+			seen[a] = struct{}{}
+			out = append(out, a)
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompileTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{"empty", ""},
+		{"unclosed", "{seed"},
+		{"stray close", "seed}"},
+		{"empty slot", "{}{seed}"},
+		{"invalid slot", "{Seed}"},
+		{"optional sep", "{seed}{sep?}{env}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileTemplate(tt.pattern); err == nil {
+				t.Errorf("CompileTemplate(%q) expected error", tt.pattern)
+			}
+		})
+	}
+}
+
+func TestTemplate_Generate(t *testing.T) {
+	tmpl := MustCompileTemplate("{env}{sep}{seed}{sep}{purpose?}")
+	dicts := map[string][]string{
+		"env":     {"dev", "prod"},
+		"seed":    {"acme"},
+		"purpose": {"logs"},
+	}
+
+	got := tmpl.Generate(dicts, []string{"-", "."}, 0)
+	want := []string{
+		"dev-acme", "dev-acme-logs", "prod-acme", "prod-acme-logs",
+		"dev.acme", "dev.acme.logs", "prod.acme", "prod.acme.logs",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Generate() = %v, want %v", got, want)
+	}
+	if n := tmpl.Count(dicts, []string{"-", "."}); n != 8 {
+		t.Errorf("Count() = %d, want 8", n)
+	}
+}
+
+func TestTemplate_OptionalSlotCollapsesSeparators(t *testing.T) {
+	tmpl := MustCompileTemplate("{env?}{sep}{seed}{sep}{year?}")
+	dicts := map[string][]string{
+		"env":  {"dev"},
+		"seed": {"acme"},
+		"year": {"2024"},
+	}
+
+	got := tmpl.Generate(dicts, []string{"-"}, 0)
+	want := []string{"acme", "acme-2024", "dev-acme", "dev-acme-2024"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Generate() = %v, want %v", got, want)
+	}
+}
+
+func TestTemplate_LiteralsAndLimit(t *testing.T) {
+	tmpl := MustCompileTemplate("{seed}-bucket-{region}")
+	dicts := map[string][]string{
+		"seed":   {"acme"},
+		"region": {"us-east-1", "eu-west-1", "ap-south-1"},
+	}
+
+	got := tmpl.Generate(dicts, nil, 2)
+	want := []string{"acme-bucket-us-east-1", "acme-bucket-eu-west-1"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Generate() = %v, want %v", got, want)
+	}
+}
+
+func TestEngine_AddTemplate(t *testing.T) {
+	e := Default()
+	e.Dictionaries = map[string][]string{"team": {"payments"}}
+
+	if err := e.AddTemplate("{team}{sep}{seed}{sep}{env}"); err != nil {
+		t.Fatalf("AddTemplate() error = %v", err)
+	}
+	if err := e.AddTemplate("{seed}{sep}{unknown}"); err == nil {
+		t.Error("AddTemplate() with unknown dictionary expected error")
+	}
+
+	names := e.Generate("acme")
+	for _, want := range []string{"payments-acme-prod", "payments.acme.staging"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Generate() missing templated name %q", want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package permutation
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// translit maps lowercase non-ASCII letters to their plain ASCII spelling.
+var translit = map[rune]string{
+	// Latin
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e", 'ĕ': "e",
+	'ğ': "g", 'ĝ': "g", 'ġ': "g", 'ģ': "g", 'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i", 'ĩ': "i",
+	'ĵ': "j", 'ķ': "k", 'ł': "l", 'ľ': "l", 'ĺ': "l", 'ļ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ņ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ŕ': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ŝ': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u", 'ũ': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+
+	// Cyrillic (Russian, Ukrainian, Bulgarian)
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye",
+	'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh",
+	'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e",
+	'ю': "yu", 'я': "ya",
+}
+
+// translitAlt holds the language-specific spellings that differ from
+// translit: German umlauts, Scandinavian vowels and the common alternative
+// romanizations of Cyrillic.
+var translitAlt = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue", 'å': "aa", 'ø': "oe",
+	'ё': "yo", 'й': "j", 'х': "h", 'ц': "c", 'щ': "sch", 'ю': "ju", 'я': "ja",
+}
+
+// Transliterate returns the lowercase ASCII spellings of s. The plain
+// spelling comes first (müller -> muller), followed by the language-specific
+// one when it differs (mueller). Combining marks and letters without a
+// transliteration are dropped. ASCII input is returned as is.
+func Transliterate(s string) []string {
+	if isASCII(s) {
+		return []string{s}
+	}
+
+	s = strings.ToLower(s)
+	plain := transliterate(s, nil)
+	alt := transliterate(s, translitAlt)
+	if alt == plain {
+		return []string{plain}
+	}
+	return []string{plain, alt}
+}
+
+// TransliterateAll replaces every non-ASCII word with its spellings,
+// keeping the order and dropping duplicates and empty results.
+func TransliterateAll(words []string) []string {
+	seen := make(map[string]struct{}, len(words))
+	out := make([]string, 0, len(words))
+	for _, w := range words {
+		for _, v := range Transliterate(w) {
+			if _, ok := seen[v]; !ok && strings.TrimSpace(v) != "" {
+				seen[v] = struct{}{}
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+func transliterate(s string, override map[rune]string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case override[r] != "":
+			b.WriteString(override[r])
+		default:
+			// Unknown letters and combining marks (İ -> i + U+0307) vanish
+			b.WriteString(translit[r])
+		}
+	}
+	return b.String()
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package permutation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"acme", []string{"acme"}},
+		{"müller", []string{"muller", "mueller"}},
+		{"Müller", []string{"muller", "mueller"}},
+		{"straße", []string{"strasse"}},
+		{"şişecam", []string{"sisecam"}},
+		{"doğuş", []string{"dogus"}},
+		{"İstanbul", []string{"istanbul"}},
+		{"société-générale", []string{"societe-generale"}},
+		{"øresund", []string{"oresund", "oeresund"}},
+		{"яндекс", []string{"yandeks", "jandeks"}},
+		{"щука", []string{"shchuka", "schuka"}},
+		{"acme 中文", []string{"acme "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Transliterate(tt.input); !slices.Equal(got, tt.expected) {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTransliterateAll(t *testing.T) {
+	got := TransliterateAll([]string{"backup", "müller", "muller", "中文"})
+	want := []string{"backup", "muller", "mueller"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("TransliterateAll() = %v, want %v", got, want)
+	}
+}
+
+func TestEngine_Generate_NonASCII(t *testing.T) {
+	e := Default()
+	names := e.Generate("Müller")
+
+	for _, want := range []string{"muller", "mueller", "muller-dev", "mueller-dev"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Generate(Müller) missing %q", want)
+		}
+	}
+	for _, name := range names {
+		if !IsValidBucketName(name) {
+			t.Errorf("Generate(Müller) produced invalid name %q", name)
+		}
+	}
+}
+
+func TestExpandSeeds_NonASCII(t *testing.T) {
+	got := ExpandSeeds([]string{"Müller GmbH", "doğuş"})
+	want := []string{"muller", "mueller", "dogus"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("ExpandSeeds() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,217 @@
+// Package rank scores candidate bucket names so the most likely ones are
+// scanned first.
+package rank
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xeloxa/s3finder/pkg/model"
+	"github.com/xeloxa/s3finder/pkg/permutation"
+)
+
+// Source identifies where a candidate name came from.
+type Source int
+
+const (
+	SourceSeed      Source = iota // Permutations of a seed keyword
+	SourceCT                      // Subdomains and CT-derived words
+	SourceCombo                   // Seed combinations
+	SourceModel                   // Offline name model
+	SourceWordlist                // User wordlist
+	SourceAI                      // AI provider
+	SourceExpansion               // Expansion from found buckets
+	SourceExtract                 // References in local artifacts
+	SourceCNAME                   // S3 targets of subdomain CNAMEs
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceSeed:
+		return "seed"
+	case SourceCT:
+		return "ct"
+	case SourceCombo:
+		return "combo"
+	case SourceModel:
+		return "model"
+	case SourceWordlist:
+		return "wordlist"
+	case SourceAI:
+		return "ai"
+	case SourceExpansion:
+		return "expansion"
+	case SourceExtract:
+		return "extract"
+	case SourceCNAME:
+		return "cname"
+	default:
+		return "unknown"
+	}
+}
+
+// SourceWeights rate how often each source yields real buckets. Names seen
+// in the wild (CNAME targets, artifacts, CT logs, curated wordlists) beat
+// blind permutations.
+var SourceWeights = map[Source]float64{
+	SourceCNAME:     1.0,
+	SourceExtract:   1.0,
+	SourceCT:        1.0,
+	SourceWordlist:  0.9,
+	SourceExpansion: 0.85,
+	SourceSeed:      0.7,
+	SourceModel:     0.7,
+	SourceAI:        0.6,
+	SourceCombo:     0.5,
+}
+
+// commonTokens are words that appear often in real bucket names, with
+// relative frequencies. They seed the token frequency table.
+var commonTokens = map[string]int{
+	"prod": 10, "dev": 10, "staging": 8, "backup": 8, "backups": 6, "logs": 8,
+	"data": 7, "assets": 7, "static": 6, "media": 5, "files": 5, "uploads": 5,
+	"test": 5, "public": 5, "cdn": 4, "web": 4, "www": 4, "images": 4,
+	"private": 3, "internal": 3, "archive": 3, "api": 3, "app": 3, "qa": 3,
+	"uat": 3, "stage": 3, "terraform": 3, "artifacts": 3, "builds": 2,
+}
+
+// Weights balance the scoring components. They need not sum to one.
+type Weights struct {
+	Source    float64
+	Frequency float64
+	Length    float64
+	Model     float64
+}
+
+// DefaultWeights returns the default component weights.
+func DefaultWeights() Weights {
+	return Weights{Source: 0.4, Frequency: 0.25, Length: 0.15, Model: 0.2}
+}
+
+// Candidate is a name waiting to be scanned.
+type Candidate struct {
+	Name   string
+	Source Source
+	Score  float64
+	Origin string // Where the name was found, e.g. the host whose CNAME named it
+}
+
+// Scorer assigns likelihood scores to candidates.
+type Scorer struct {
+	Weights Weights
+	Model   *model.Model // Optional
+	Seeds   []string
+
+	freq    map[string]int
+	maxFreq int
+}
+
+// NewScorer creates a Scorer with the built-in token frequencies.
+func NewScorer(m *model.Model, seeds []string) *Scorer {
+	s := &Scorer{
+		Weights: DefaultWeights(),
+		Model:   m,
+		Seeds:   permutation.NormalizeSeeds(seeds),
+		freq:    make(map[string]int),
+	}
+	for token, n := range commonTokens {
+		s.addToken(token, n)
+	}
+	return s
+}
+
+// Observe counts the tokens of names that are known to exist or were seen in
+// recon, raising the score of candidates that share them.
+func (s *Scorer) Observe(names ...string) {
+	for _, name := range names {
+		for _, token := range permutation.Tokenize(name) {
+			s.addToken(token, 1)
+		}
+	}
+}
+
+func (s *Scorer) addToken(token string, n int) {
+	s.freq[token] += n
+	s.maxFreq = max(s.maxFreq, s.freq[token])
+}
+
+// Score returns the likelihood score of a candidate. Higher is better.
+func (s *Scorer) Score(c Candidate) float64 {
+	tokens := permutation.Tokenize(c.Name)
+	seed := s.seedFor(c.Name)
+	seedTokens := permutation.Tokenize(seed)
+
+	// Token frequency, ignoring the seed which every candidate shares
+	var freqSum float64
+	counted := 0
+	for _, t := range tokens {
+		if slices.Contains(seedTokens, t) {
+			continue
+		}
+		counted++
+		if s.maxFreq > 0 {
+			freqSum += math.Log1p(float64(s.freq[t])) / math.Log1p(float64(s.maxFreq))
+		}
+	}
+	freq := 0.0
+	if counted > 0 {
+		freq = freqSum / float64(counted)
+	}
+
+	// Fewer extra words is more likely
+	length := 1.0 / float64(1+counted)
+
+	score := s.Weights.Source*SourceWeights[c.Source] +
+		s.Weights.Frequency*freq +
+		s.Weights.Length*length
+
+	if s.Model != nil && seed != "" {
+		score += s.Weights.Model * math.Exp(s.Model.Score(c.Name, seed))
+	}
+
+	return score
+}
+
+// Rank scores the candidates and sorts them best first. Ties keep their
+// original order.
+func (s *Scorer) Rank(candidates []Candidate) {
+	for i := range candidates {
+		candidates[i].Score = s.Score(candidates[i])
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
+
+// seedFor returns the longest seed contained in name, or "".
+func (s *Scorer) seedFor(name string) string {
+	best := ""
+	for _, seed := range s.Seeds {
+		if len(seed) > len(best) && strings.Contains(name, seed) {
+			best = seed
+		}
+	}
+	return best
+}
+
+// Names returns the candidate names in order.
+func Names(candidates []Candidate) []string {
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Origins maps candidate names to their origin, for candidates that have one.
+func Origins(candidates []Candidate) map[string]string {
+	origins := make(map[string]string)
+	for _, c := range candidates {
+		if c.Origin != "" {
+			origins[c.Name] = c.Origin
+		}
+	}
+	return origins
+}
@@ -0,0 +1,144 @@
+package rank
+
+import (
+	"testing"
+
+	"github.com/xeloxa/s3finder/pkg/model"
+)
+
+func TestSource_String(t *testing.T) {
+	tests := []struct {
+		source   Source
+		expected string
+	}{
+		{SourceSeed, "seed"},
+		{SourceCT, "ct"},
+		{SourceCombo, "combo"},
+		{SourceModel, "model"},
+		{SourceWordlist, "wordlist"},
+		{SourceAI, "ai"},
+		{SourceExpansion, "expansion"},
+		{SourceExtract, "extract"},
+		{SourceCNAME, "cname"},
+		{Source(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.source.String(); got != tt.expected {
+			t.Errorf("Source(%d).String() = %q, want %q", tt.source, got, tt.expected)
+		}
+	}
+}
+
+func TestScorer_Score(t *testing.T) {
+	s := NewScorer(nil, []string{"acme"})
+
+	tests := []struct {
+		name   string
+		better Candidate
+		worse  Candidate
+	}{
+		{
+			"common words beat rare ones",
+			Candidate{Name: "acme-prod", Source: SourceSeed},
+			Candidate{Name: "acme-zebra", Source: SourceSeed},
+		},
+		{
+			"short names beat long ones",
+			Candidate{Name: "acme-logs", Source: SourceSeed},
+			Candidate{Name: "acme-logs-archive-old", Source: SourceSeed},
+		},
+		{
+			"CT names beat combinations",
+			Candidate{Name: "acme-globex", Source: SourceCT},
+			Candidate{Name: "acme-globex", Source: SourceCombo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if b, w := s.Score(tt.better), s.Score(tt.worse); b <= w {
+				t.Errorf("Score(%s) = %f, want > Score(%s) = %f", tt.better.Name, b, tt.worse.Name, w)
+			}
+		})
+	}
+}
+
+func TestScorer_Observe(t *testing.T) {
+	s := NewScorer(nil, []string{"acme"})
+	c := Candidate{Name: "acme-zebra", Source: SourceSeed}
+
+	before := s.Score(c)
+	s.Observe("globex-zebra", "initech-zebra-logs")
+	if after := s.Score(c); after <= before {
+		t.Errorf("Score after Observe = %f, want > %f", after, before)
+	}
+}
+
+func TestScorer_Model(t *testing.T) {
+	m := model.Train([]string{
+		"globex-prod-logs", "initech-prod-logs", "hooli-prod-logs",
+	}, model.TrainOptions{})
+	s := NewScorer(m, []string{"acme"})
+
+	likely := s.Score(Candidate{Name: "acme-prod-logs", Source: SourceModel})
+	unlikely := s.Score(Candidate{Name: "logs-prod-acme", Source: SourceModel})
+	if likely <= unlikely {
+		t.Errorf("model-likely name scored %f, want > %f", likely, unlikely)
+	}
+}
+
+func TestScorer_Rank(t *testing.T) {
+	s := NewScorer(nil, []string{"acme"})
+	candidates := []Candidate{
+		{Name: "acme-zebra-unicorn", Source: SourceCombo},
+		{Name: "acme-prod", Source: SourceCT},
+		{Name: "acme-dev", Source: SourceSeed},
+	}
+
+	s.Rank(candidates)
+
+	names := Names(candidates)
+	expected := []string{"acme-prod", "acme-dev", "acme-zebra-unicorn"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Rank() order = %v, want %v", names, expected)
+		}
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Score > candidates[i-1].Score {
+			t.Errorf("candidates not sorted by score: %v", candidates)
+		}
+	}
+}
+
+func TestScorer_Rank_Stable(t *testing.T) {
+	s := NewScorer(nil, nil)
+	candidates := []Candidate{
+		{Name: "alpha", Source: SourceWordlist},
+		{Name: "bravo", Source: SourceWordlist},
+		{Name: "charlie", Source: SourceWordlist},
+	}
+
+	s.Rank(candidates)
+
+	names := Names(candidates)
+	expected := []string{"alpha", "bravo", "charlie"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Rank() order = %v, want original order %v", names, expected)
+		}
+	}
+}
+
+func TestOrigins(t *testing.T) {
+	candidates := []Candidate{
+		{Name: "acme-assets", Source: SourceCNAME, Origin: "assets.acme.com"},
+		{Name: "acme-backup", Source: SourceSeed},
+	}
+
+	origins := Origins(candidates)
+	if len(origins) != 1 || origins["acme-assets"] != "assets.acme.com" {
+		t.Errorf("Origins() = %v, want only acme-assets from assets.acme.com", origins)
+	}
+}
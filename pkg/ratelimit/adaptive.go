@@ -12,6 +12,7 @@ import (
 // rate limiting that automatically adjusts based on server responses.
 type AdaptiveLimiter struct {
 	maxRPS         float64
+	minRPS         float64
 	currentRPS     atomic.Value // float64
 	limiter        *rate.Limiter
 	consecutive429 int64
@@ -20,13 +21,24 @@ type AdaptiveLimiter struct {
 }
 
 // New creates an AdaptiveLimiter with the specified maximum RPS ceiling.
+// Throttling never backs off below 20 RPS.
 func New(maxRPS float64) *AdaptiveLimiter {
+	return NewWithFloor(maxRPS, 20)
+}
+
+// NewWithFloor creates an AdaptiveLimiter that backs off no lower than
+// minRPS. The floor is capped at the ceiling.
+func NewWithFloor(maxRPS, minRPS float64) *AdaptiveLimiter {
 	if maxRPS <= 0 {
 		maxRPS = 100
 	}
+	if minRPS <= 0 || minRPS > maxRPS {
+		minRPS = maxRPS
+	}
 
 	a := &AdaptiveLimiter{
 		maxRPS:  maxRPS,
+		minRPS:  minRPS,
 		limiter: rate.NewLimiter(rate.Limit(maxRPS), int(maxRPS)),
 	}
 	a.currentRPS.Store(maxRPS)
@@ -53,8 +65,8 @@ func (a *AdaptiveLimiter) RecordResponse(statusCode int) {
 		// Multiplicative decrease: halve RPS after 3 consecutive throttles
 		if a.consecutive429 >= 3 {
 			newRPS := current * 0.5
-			if newRPS < 20 {
-				newRPS = 20 // floor at 20 RPS for usable performance
+			if newRPS < a.minRPS {
+				newRPS = a.minRPS
 			}
 			a.currentRPS.Store(newRPS)
 			a.limiter.SetLimit(rate.Limit(newRPS))
@@ -69,8 +81,8 @@ func (a *AdaptiveLimiter) RecordResponse(statusCode int) {
 		// Only decrease after 5 consecutive network failures
 		if a.consecutive429 >= 5 {
 			newRPS := current * 0.7
-			if newRPS < 20 {
-				newRPS = 20
+			if newRPS < a.minRPS {
+				newRPS = a.minRPS
 			}
 			a.currentRPS.Store(newRPS)
 			a.limiter.SetLimit(rate.Limit(newRPS))
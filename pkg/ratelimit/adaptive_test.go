@@ -215,3 +215,19 @@ func TestCurrentRPS_Concurrent(t *testing.T) {
 		t.Errorf("CurrentRPS() = %v, should be between 10 and 500", rps)
 	}
 }
+
+func TestRecordResponse_CustomFloor(t *testing.T) {
+	limiter := NewWithFloor(8, 2)
+
+	for i := 0; i < 30; i++ {
+		limiter.RecordResponse(503)
+	}
+
+	// A floor below a low ceiling lets throttling still back off
+	if limiter.CurrentRPS() >= 8 {
+		t.Errorf("CurrentRPS() = %v, want below ceiling 8", limiter.CurrentRPS())
+	}
+	if limiter.CurrentRPS() < 2 {
+		t.Errorf("CurrentRPS() = %v, should not go below 2", limiter.CurrentRPS())
+	}
+}
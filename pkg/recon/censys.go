@@ -0,0 +1,128 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// censysPages bounds cursor pagination.
+const censysPages = 5
+
+// censysResponse is the relevant part of a Censys v2 certificate search.
+type censysResponse struct {
+	Result struct {
+		Hits []struct {
+			Names []string `json:"names"`
+		} `json:"hits"`
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	} `json:"result"`
+}
+
+// Censys queries a Censys-style certificate search API with basic auth.
+type Censys struct {
+	httpClient *http.Client
+	apiID      string
+	apiSecret  string
+	baseURL    string
+}
+
+// NewCensys creates a Censys source. baseURL may point at any API that
+// speaks the Censys v2 certificate search protocol ("" = search.censys.io).
+func NewCensys(timeout time.Duration, apiID, apiSecret, baseURL string) *Censys {
+	if baseURL == "" {
+		baseURL = "https://search.censys.io"
+	}
+	return &Censys{
+		httpClient: &http.Client{Timeout: timeout},
+		apiID:      apiID,
+		apiSecret:  apiSecret,
+		baseURL:    baseURL,
+	}
+}
+
+// Name implements Source.
+func (c *Censys) Name() string {
+	return "censys"
+}
+
+// Subdomains implements Source.
+func (c *Censys) Subdomains(ctx context.Context, domain string) ([]string, error) {
+	domain = cleanDomain(domain)
+	if domain == "" {
+		return nil, fmt.Errorf("invalid domain")
+	}
+	if c.apiID == "" || c.apiSecret == "" {
+		return nil, fmt.Errorf("censys requires an API ID and secret")
+	}
+
+	seen := make(map[string]struct{})
+	var subdomains []string
+	cursor := ""
+
+	for page := 0; page < censysPages; page++ {
+		q := url.Values{}
+		q.Set("q", "names: "+domain)
+		q.Set("per_page", "100")
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v2/certificates/search?"+q.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("User-Agent", "s3finder/1.0")
+		req.Header.Set("Accept", "application/json")
+		req.SetBasicAuth(c.apiID, c.apiSecret)
+
+		body, err := c.fetch(req)
+		if err != nil {
+			if len(subdomains) > 0 {
+				return subdomains, nil
+			}
+			return nil, err
+		}
+
+		for _, hit := range body.Result.Hits {
+			for _, name := range hit.Names {
+				if name, ok := normalizeSubdomain(name, domain); ok {
+					if _, dup := seen[name]; !dup {
+						seen[name] = struct{}{}
+						subdomains = append(subdomains, name)
+					}
+				}
+			}
+		}
+
+		cursor = body.Result.Links.Next
+		if cursor == "" || len(body.Result.Hits) == 0 {
+			break
+		}
+	}
+
+	return subdomains, nil
+}
+
+func (c *Censys) fetch(req *http.Request) (*censysResponse, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("censys request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("censys returned status %d", resp.StatusCode)
+	}
+
+	var body censysResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse censys response: %w", err)
+	}
+	return &body, nil
+}
@@ -0,0 +1,117 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// certSpotterPages bounds pagination; unauthenticated clients are limited
+// to a few requests per hour anyway.
+const certSpotterPages = 5
+
+// certSpotterIssuance is one entry of the CertSpotter issuances API.
+type certSpotterIssuance struct {
+	ID       string   `json:"id"`
+	DNSNames []string `json:"dns_names"`
+}
+
+// CertSpotter queries the SSLMate CertSpotter issuances API.
+type CertSpotter struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+}
+
+// NewCertSpotter creates a CertSpotter source. apiKey is optional and
+// raises the rate limit.
+func NewCertSpotter(timeout time.Duration, apiKey string) *CertSpotter {
+	return &CertSpotter{
+		httpClient: &http.Client{Timeout: timeout},
+		apiKey:     apiKey,
+		baseURL:    "https://api.certspotter.com",
+	}
+}
+
+// Name implements Source.
+func (c *CertSpotter) Name() string {
+	return "certspotter"
+}
+
+// Subdomains implements Source.
+func (c *CertSpotter) Subdomains(ctx context.Context, domain string) ([]string, error) {
+	domain = cleanDomain(domain)
+	if domain == "" {
+		return nil, fmt.Errorf("invalid domain")
+	}
+
+	seen := make(map[string]struct{})
+	var subdomains []string
+	after := ""
+
+	for page := 0; page < certSpotterPages; page++ {
+		q := url.Values{}
+		q.Set("domain", domain)
+		q.Set("include_subdomains", "true")
+		q.Set("expand", "dns_names")
+		if after != "" {
+			q.Set("after", after)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/issuances?"+q.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("User-Agent", "s3finder/1.0")
+		if c.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		}
+
+		issuances, err := c.fetch(req)
+		if err != nil {
+			// Keep what earlier pages returned
+			if len(subdomains) > 0 {
+				return subdomains, nil
+			}
+			return nil, err
+		}
+		if len(issuances) == 0 {
+			break
+		}
+
+		for _, iss := range issuances {
+			for _, name := range iss.DNSNames {
+				if name, ok := normalizeSubdomain(name, domain); ok {
+					if _, dup := seen[name]; !dup {
+						seen[name] = struct{}{}
+						subdomains = append(subdomains, name)
+					}
+				}
+			}
+		}
+		after = issuances[len(issuances)-1].ID
+	}
+
+	return subdomains, nil
+}
+
+func (c *CertSpotter) fetch(req *http.Request) ([]certSpotterIssuance, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("certspotter request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("certspotter returned status %d", resp.StatusCode)
+	}
+
+	var issuances []certSpotterIssuance
+	if err := json.NewDecoder(resp.Body).Decode(&issuances); err != nil {
+		return nil, fmt.Errorf("failed to parse certspotter response: %w", err)
+	}
+	return issuances, nil
+}
@@ -0,0 +1,77 @@
+package recon
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xeloxa/s3finder/pkg/extract"
+)
+
+// CNAMEResolver returns the CNAME chain of a host, first hop first.
+// *dns.Resolver implements it.
+type CNAMEResolver interface {
+	CNAMEChain(ctx context.Context, host string) ([]string, error)
+}
+
+// CNAMERecord is a host that resolves through one or more CNAMEs.
+type CNAMERecord struct {
+	Host   string
+	Chain  []string
+	Bucket string // Named by an S3 target in the chain, "" if none
+}
+
+// SweepCNAMEs resolves hosts concurrently and returns those with a CNAME, in
+// host order. Hosts pointing at *.s3*.amazonaws.com carry the bucket name,
+// which is a much stronger lead than any generated candidate. Lookup
+// failures are skipped.
+func SweepCNAMEs(ctx context.Context, resolver CNAMEResolver, hosts []string, workers int) []CNAMERecord {
+	if workers <= 0 {
+		workers = 20
+	}
+
+	records := make([]CNAMERecord, len(hosts))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(hosts)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				chain, err := resolver.CNAMEChain(ctx, hosts[i])
+				if err != nil && len(chain) == 0 {
+					continue
+				}
+				records[i] = CNAMERecord{Host: hosts[i], Chain: chain, Bucket: chainBucket(chain)}
+			}
+		}()
+	}
+
+feed:
+	for i := range hosts {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var found []CNAMERecord
+	for _, r := range records {
+		if len(r.Chain) > 0 {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// chainBucket returns the bucket named by the first S3 target in chain.
+func chainBucket(chain []string) string {
+	for _, target := range chain {
+		if buckets := extract.Find(target); len(buckets) > 0 {
+			return buckets[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// fakeResolver returns fixed CNAME chains.
+type fakeResolver map[string][]string
+
+func (f fakeResolver) CNAMEChain(ctx context.Context, host string) ([]string, error) {
+	if host == "broken.acme.com" {
+		return nil, errors.New("timeout")
+	}
+	return f[host], nil
+}
+
+func TestSweepCNAMEs(t *testing.T) {
+	resolver := fakeResolver{
+		"assets.acme.com": {"acme-assets.s3.amazonaws.com", "s3-1-w.amazonaws.com"},
+		"www.acme.com":    {"d111111abcdef8.cloudfront.net"},
+		"static.acme.com": {"static.acme.com.s3-website-us-east-1.amazonaws.com"},
+		"eu.acme.com":     {"acme-eu.s3.dualstack.eu-west-1.amazonaws.com"},
+	}
+	hosts := []string{"assets.acme.com", "mail.acme.com", "www.acme.com", "broken.acme.com", "static.acme.com", "eu.acme.com"}
+
+	got := SweepCNAMEs(context.Background(), resolver, hosts, 3)
+
+	expected := []CNAMERecord{
+		{Host: "assets.acme.com", Chain: resolver["assets.acme.com"], Bucket: "acme-assets"},
+		{Host: "www.acme.com", Chain: resolver["www.acme.com"]},
+		{Host: "static.acme.com", Chain: resolver["static.acme.com"], Bucket: "static.acme.com"},
+		{Host: "eu.acme.com", Chain: resolver["eu.acme.com"], Bucket: "acme-eu"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("SweepCNAMEs() = %v, want %v", got, expected)
+	}
+	for i := range expected {
+		if got[i].Host != expected[i].Host || got[i].Bucket != expected[i].Bucket || !slices.Equal(got[i].Chain, expected[i].Chain) {
+			t.Errorf("SweepCNAMEs()[%d] = %+v, want %+v", i, got[i], expected[i])
+		}
+	}
+}
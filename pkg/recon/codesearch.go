@@ -0,0 +1,291 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xeloxa/s3finder/pkg/extract"
+)
+
+// codeSearchTerms are combined with the target in separate queries.
+var codeSearchTerms = []string{"s3", "bucket", "amazonaws"}
+
+// CodeSearchConfig configures a CodeSearch source.
+type CodeSearchConfig struct {
+	Timeout  time.Duration // Per request
+	Provider string        // "github" or "gitlab"
+	BaseURL  string        // "" = api.github.com or gitlab.com
+	Token    string        // Required by both APIs for code search
+	MaxPages int           // Pages of 100 results per query (0 = 1)
+	MaxWait  time.Duration // Longest rate-limit wait before giving up (0 = 2m)
+}
+
+// CodeSearch searches public code on GitHub or GitLab, or any server with a
+// compatible API, for files mentioning the target next to S3 terms and
+// extracts the bucket names in the matching snippets.
+type CodeSearch struct {
+	httpClient *http.Client
+	provider   string
+	baseURL    string
+	token      string
+	maxPages   int
+	maxWait    time.Duration
+	now        func() time.Time
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// NewCodeSearch creates a code search source.
+func NewCodeSearch(cfg *CodeSearchConfig) (*CodeSearch, error) {
+	c := &CodeSearch{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		provider:   strings.ToLower(cfg.Provider),
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		token:      cfg.Token,
+		maxPages:   cfg.MaxPages,
+		maxWait:    cfg.MaxWait,
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+
+	switch c.provider {
+	case "github":
+		if c.baseURL == "" {
+			c.baseURL = "https://api.github.com"
+		}
+	case "gitlab":
+		if c.baseURL == "" {
+			c.baseURL = "https://gitlab.com"
+		}
+	default:
+		return nil, fmt.Errorf("unknown code search provider %q (valid: github, gitlab)", cfg.Provider)
+	}
+	if c.maxPages <= 0 {
+		c.maxPages = 1
+	}
+	if c.maxWait <= 0 {
+		c.maxWait = 2 * time.Minute
+	}
+	return c, nil
+}
+
+// Name implements BucketSource.
+func (c *CodeSearch) Name() string {
+	return c.provider
+}
+
+// Buckets implements BucketSource. target is a domain or seed keyword.
+// Reference.File is the URL of the matching file. When the rate limit or a
+// later request stops the search, the references found so far are
+// returned.
+func (c *CodeSearch) Buckets(ctx context.Context, target string) ([]extract.Reference, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("empty code search target")
+	}
+	if c.token == "" {
+		return nil, fmt.Errorf("%s code search requires a token", c.provider)
+	}
+
+	var refs []extract.Reference
+	for _, term := range codeSearchTerms {
+		query := fmt.Sprintf("%q %s", target, term)
+		for page := 1; page <= c.maxPages; page++ {
+			found, more, err := c.search(ctx, query, page)
+			refs = append(refs, found...)
+			if err != nil {
+				if len(refs) > 0 {
+					return refs, nil
+				}
+				return nil, err
+			}
+			if !more {
+				break
+			}
+		}
+	}
+	return refs, nil
+}
+
+// search runs one page of a query, waiting out the rate limit once when
+// the server rejects it. When the page succeeds but the quota reset is
+// beyond MaxWait, its references are returned along with the wait error.
+func (c *CodeSearch) search(ctx context.Context, query string, page int) ([]extract.Reference, bool, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, query, page)
+		if err != nil {
+			return nil, false, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s request failed: %w", c.provider, err)
+		}
+
+		wait, limited := c.rateLimitWait(resp)
+		if limited && attempt == 0 {
+			resp.Body.Close()
+			if err := c.waitFor(ctx, wait); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+
+		refs, more, err := c.decode(resp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Out of quota: wait for the window to reset before the next query
+		if wait > 0 {
+			if err := c.waitFor(ctx, wait); err != nil {
+				return refs, false, err
+			}
+		}
+		return refs, more, nil
+	}
+}
+
+func (c *CodeSearch) newRequest(ctx context.Context, query string, page int) (*http.Request, error) {
+	q := url.Values{}
+	var endpoint string
+	if c.provider == "github" {
+		q.Set("q", query)
+		q.Set("per_page", "100")
+		q.Set("page", strconv.Itoa(page))
+		endpoint = c.baseURL + "/search/code?" + q.Encode()
+	} else {
+		q.Set("scope", "blobs")
+		q.Set("search", query)
+		q.Set("per_page", "100")
+		q.Set("page", strconv.Itoa(page))
+		endpoint = c.baseURL + "/api/v4/search?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "s3finder/1.0")
+	if c.provider == "github" {
+		req.Header.Set("Accept", "application/vnd.github.text-match+json")
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+	return req, nil
+}
+
+// rateLimitWait reads the rate-limit headers of a response. It returns how
+// long to wait before the next request (0 when quota is left) and whether
+// the request itself was rejected by the limit. GitHub sends
+// X-RateLimit-Remaining/Reset, GitLab RateLimit-Remaining/Reset; both may
+// send Retry-After.
+func (c *CodeSearch) rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	var wait time.Duration
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			wait = time.Duration(secs) * time.Second
+		}
+	}
+
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	reset := resp.Header.Get("X-RateLimit-Reset")
+	if remaining == "" {
+		remaining = resp.Header.Get("RateLimit-Remaining")
+		reset = resp.Header.Get("RateLimit-Reset")
+	}
+	if remaining == "0" && wait == 0 {
+		if unix, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			wait = max(time.Unix(unix, 0).Sub(c.now()), time.Second)
+		}
+	}
+
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusForbidden && (remaining == "0" || wait > 0)
+	if limited && wait == 0 {
+		wait = time.Minute
+	}
+	return wait, limited
+}
+
+// waitFor sleeps for d unless it exceeds MaxWait.
+func (c *CodeSearch) waitFor(ctx context.Context, d time.Duration) error {
+	if d > c.maxWait {
+		return fmt.Errorf("%s rate limit resets in %s", c.provider, d.Round(time.Second))
+	}
+	return c.sleep(ctx, d)
+}
+
+// githubResponse is the relevant part of a GitHub code search response.
+type githubResponse struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		HTMLURL     string `json:"html_url"`
+		TextMatches []struct {
+			Fragment string `json:"fragment"`
+		} `json:"text_matches"`
+	} `json:"items"`
+}
+
+// gitlabBlob is one result of a GitLab blob search.
+type gitlabBlob struct {
+	Path      string `json:"path"`
+	Ref       string `json:"ref"`
+	Data      string `json:"data"`
+	Startline int    `json:"startline"`
+	ProjectID int    `json:"project_id"`
+}
+
+// decode parses a search response into references and reports whether
+// another page may follow.
+func (c *CodeSearch) decode(resp *http.Response) ([]extract.Reference, bool, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("%s returned status %d", c.provider, resp.StatusCode)
+	}
+
+	var refs []extract.Reference
+	if c.provider == "github" {
+		var result githubResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, false, fmt.Errorf("failed to parse github response: %w", err)
+		}
+		for _, item := range result.Items {
+			for _, m := range item.TextMatches {
+				for _, bucket := range extract.Find(m.Fragment) {
+					refs = append(refs, extract.Reference{Bucket: bucket, File: item.HTMLURL})
+				}
+			}
+		}
+		return refs, len(result.Items) == 100, nil
+	}
+
+	var blobs []gitlabBlob
+	if err := json.NewDecoder(resp.Body).Decode(&blobs); err != nil {
+		return nil, false, fmt.Errorf("failed to parse gitlab response: %w", err)
+	}
+	for _, b := range blobs {
+		file := fmt.Sprintf("%s/projects/%d/%s@%s", c.baseURL, b.ProjectID, b.Path, b.Ref)
+		for i, line := range strings.Split(b.Data, "\n") {
+			for _, bucket := range extract.Find(line) {
+				refs = append(refs, extract.Reference{Bucket: bucket, File: file, Line: b.Startline + i})
+			}
+		}
+	}
+	return refs, resp.Header.Get("X-Next-Page") != "", nil
+}
+
+// sleepContext waits for d or until ctx ends.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
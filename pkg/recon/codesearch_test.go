@@ -0,0 +1,196 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCodeSearch(t *testing.T, provider, baseURL string) (*CodeSearch, *[]time.Duration) {
+	t.Helper()
+	c, err := NewCodeSearch(&CodeSearchConfig{Timeout: time.Second, Provider: provider, BaseURL: baseURL, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var waits []time.Duration
+	c.now = func() time.Time { return time.Unix(1000, 0) }
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return c, &waits
+}
+
+func TestCodeSearch_GitHub(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/code" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("unexpected request %s with auth %q", r.URL, r.Header.Get("Authorization"))
+		}
+		if !strings.Contains(r.Header.Get("Accept"), "text-match") {
+			t.Errorf("Accept = %q, want text-match media type", r.Header.Get("Accept"))
+		}
+		q := r.URL.Query().Get("q")
+		queries = append(queries, q)
+
+		if !strings.HasSuffix(q, " s3") {
+			w.Write([]byte(`{"total_count": 0, "items": []}`))
+			return
+		}
+		w.Write([]byte(`{"total_count": 1, "items": [{
+			"html_url": "https://github.com/acme/app/blob/main/config.yml",
+			"text_matches": [{"fragment": "uploads: s3://acme-uploads/\nhost: acme.com"}]
+		}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := newTestCodeSearch(t, "github", server.URL)
+	refs, err := c.Buckets(context.Background(), "acme.com")
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+
+	expected := []string{`"acme.com" s3`, `"acme.com" bucket`, `"acme.com" amazonaws`}
+	if !slices.Equal(queries, expected) {
+		t.Errorf("queries = %v, want %v", queries, expected)
+	}
+	if len(refs) != 1 || refs[0].Bucket != "acme-uploads" || refs[0].File != "https://github.com/acme/app/blob/main/config.yml" {
+		t.Errorf("Buckets() = %+v", refs)
+	}
+}
+
+func TestCodeSearch_GitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/search" || r.URL.Query().Get("scope") != "blobs" || r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.URL.Query().Get("page") == "1" && strings.HasSuffix(r.URL.Query().Get("search"), " bucket") {
+			json.NewEncoder(w).Encode([]gitlabBlob{{
+				Path:      "terraform/main.tf",
+				Ref:       "main",
+				Data:      "resource \"aws_s3_bucket\" \"logs\" {\n  bucket = \"acme-tf-logs\"\n}",
+				Startline: 10,
+				ProjectID: 7,
+			}})
+			return
+		}
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	c, _ := newTestCodeSearch(t, "gitlab", server.URL)
+	refs, err := c.Buckets(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+	if len(refs) != 1 {
+		t.Fatalf("Buckets() = %+v, want one reference", refs)
+	}
+	if want := server.URL + "/projects/7/terraform/main.tf@main:11"; refs[0].Bucket != "acme-tf-logs" || refs[0].Location() != want {
+		t.Errorf("Buckets() = %s at %s, want acme-tf-logs at %s", refs[0].Bucket, refs[0].Location(), want)
+	}
+}
+
+func TestCodeSearch_RateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			// Rejected: wait for the reset and retry
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(1030))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		case 2:
+			// Accepted, but the quota is now used up
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(1010))
+		case 3:
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"items": []}`))
+	}))
+	defer server.Close()
+
+	c, waits := newTestCodeSearch(t, "github", server.URL)
+	if _, err := c.Buckets(context.Background(), "acme"); err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+
+	expected := []time.Duration{30 * time.Second, 10 * time.Second, 5 * time.Second}
+	if !slices.Equal(*waits, expected) {
+		t.Errorf("waits = %v, want %v", *waits, expected)
+	}
+	if requests != 5 {
+		t.Errorf("requests = %d, want 5", requests)
+	}
+}
+
+func TestCodeSearch_MaxWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c, waits := newTestCodeSearch(t, "github", server.URL)
+	if _, err := c.Buckets(context.Background(), "acme"); err == nil {
+		t.Error("Buckets() expected error when the reset is beyond MaxWait")
+	}
+	if len(*waits) != 0 {
+		t.Errorf("waited %v, want no wait", *waits)
+	}
+}
+
+func TestCodeSearch_MaxWaitAfterPage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Accepted, but the quota resets in an hour
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(1000+3600))
+		w.Write([]byte(`{"total_count": 1, "items": [{
+			"html_url": "https://github.com/acme/app/blob/main/.env",
+			"text_matches": [{"fragment": "S3_BUCKET=acme-backups.s3.amazonaws.com"}]
+		}]}`))
+	}))
+	defer server.Close()
+
+	c, waits := newTestCodeSearch(t, "github", server.URL)
+	refs, err := c.Buckets(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+	if got := bucketsOf(refs); !slices.Equal(got, []string{"acme-backups"}) {
+		t.Errorf("Buckets() = %v, want [acme-backups]", got)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want the search to stop after the first page", requests)
+	}
+	if len(*waits) != 0 {
+		t.Errorf("waited %v, want no wait", *waits)
+	}
+}
+
+func TestNewCodeSearch_Errors(t *testing.T) {
+	if _, err := NewCodeSearch(&CodeSearchConfig{Provider: "bitbucket"}); err == nil {
+		t.Error("NewCodeSearch() expected error for unknown provider")
+	}
+
+	c, err := NewCodeSearch(&CodeSearchConfig{Provider: "github"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Buckets(context.Background(), "acme"); err == nil {
+		t.Error("Buckets() expected error without a token")
+	}
+}
@@ -0,0 +1,332 @@
+package recon
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/xeloxa/s3finder/pkg/extract"
+)
+
+// CrawlerConfig configures a Crawler.
+type CrawlerConfig struct {
+	Timeout       time.Duration // Per request
+	MaxDepth      int           // Links followed from the start page (0 = start page only)
+	MaxPages      int           // Pages fetched at most (0 = 100)
+	RespectRobots bool          // Skip paths disallowed by robots.txt
+	MaxBodySize   int64         // Larger responses are truncated (0 = 5 MB)
+}
+
+// Crawler walks a website and extracts bucket references from its HTML,
+// JavaScript and CSS. Only pages on the start page's origin are fetched;
+// when the start page redirects, e.g. from acme.com to www.acme.com or from
+// http to https, the origin it lands on is used.
+type Crawler struct {
+	httpClient    *http.Client
+	maxDepth      int
+	maxPages      int
+	respectRobots bool
+	maxBodySize   int64
+}
+
+// NewCrawler creates a crawler.
+func NewCrawler(cfg *CrawlerConfig) *Crawler {
+	c := &Crawler{
+		httpClient:    &http.Client{Timeout: cfg.Timeout},
+		maxDepth:      max(cfg.MaxDepth, 0),
+		maxPages:      cfg.MaxPages,
+		respectRobots: cfg.RespectRobots,
+		maxBodySize:   cfg.MaxBodySize,
+	}
+	if c.maxPages <= 0 {
+		c.maxPages = 100
+	}
+	if c.maxBodySize <= 0 {
+		c.maxBodySize = 5 << 20
+	}
+	return c
+}
+
+// Name implements BucketSource.
+func (c *Crawler) Name() string {
+	return "crawler"
+}
+
+// crawlItem is a queued page.
+type crawlItem struct {
+	u     *url.URL
+	depth int
+}
+
+// Buckets implements BucketSource. target is a URL or a bare host, which is
+// fetched over HTTPS. The crawl is breadth-first; when ctx ends, the
+// references found so far are returned. The start page is always fetched;
+// robots.txt applies to the pages linked from it.
+func (c *Crawler) Buckets(ctx context.Context, target string) ([]extract.Reference, error) {
+	start, err := startURL(target)
+	if err != nil {
+		return nil, err
+	}
+
+	final, body, html, err := c.fetch(ctx, start, nil)
+	if err != nil {
+		return nil, err
+	}
+	origin := &url.URL{Scheme: final.Scheme, Host: final.Host}
+
+	var disallowed []string
+	if c.respectRobots {
+		disallowed = c.robots(ctx, origin)
+	}
+
+	var refs []extract.Reference
+	var queue []crawlItem
+	visited := map[string]struct{}{pageKey(final): {}}
+
+	// scan extracts references from a page and queues its links
+	scan := func(page *url.URL, body []byte, html bool, depth int) {
+		extract.Data(page.String(), body, func(r extract.Reference) {
+			refs = append(refs, r)
+		})
+		if depth >= c.maxDepth {
+			return
+		}
+		for _, link := range pageLinks(page, body, html) {
+			if !sameOrigin(link, origin) || !crawlable(link) {
+				continue
+			}
+			if _, ok := visited[pageKey(link)]; ok {
+				continue
+			}
+			visited[pageKey(link)] = struct{}{}
+			queue = append(queue, crawlItem{link, depth + 1})
+		}
+	}
+	scan(final, body, html, 0)
+
+	for pages := 1; len(queue) > 0 && pages < c.maxPages && ctx.Err() == nil; {
+		item := queue[0]
+		queue = queue[1:]
+		if isDisallowed(disallowed, item.u) {
+			continue
+		}
+
+		pages++
+		final, body, html, err := c.fetch(ctx, item.u, origin)
+		if err != nil {
+			continue
+		}
+		scan(final, body, html, item.depth)
+	}
+
+	return refs, nil
+}
+
+// startURL turns a crawl target into an absolute URL.
+func startURL(target string) (*url.URL, error) {
+	target = strings.TrimSpace(target)
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid crawl target %q", target)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u, nil
+}
+
+// textTypes are the content types whose bodies are scanned.
+var textTypes = []string{"text/", "javascript", "json", "xml", "css"}
+
+// maxRedirects bounds the redirects followed for one page.
+const maxRedirects = 10
+
+// fetch downloads a page. It returns the URL after redirects and whether the
+// body is HTML. Redirects are followed anywhere when origin is nil and
+// stopped before leaving origin otherwise. Responses that are not text are
+// errors.
+func (c *Crawler) fetch(ctx context.Context, u, origin *url.URL) (*url.URL, []byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "s3finder/1.0")
+
+	client := *c.httpClient
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("%s: stopped after %d redirects", u, maxRedirects)
+		}
+		if origin != nil && !sameOrigin(next.URL, origin) {
+			return fmt.Errorf("%s redirected off-origin to %s", u, next.URL)
+		}
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("crawl request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, false, fmt.Errorf("%s returned status %d", u, resp.StatusCode)
+	}
+	final := resp.Request.URL
+
+	ctype := strings.ToLower(resp.Header.Get("Content-Type"))
+	text := ctype == ""
+	for _, t := range textTypes {
+		text = text || strings.Contains(ctype, t)
+	}
+	if !text {
+		return nil, nil, false, fmt.Errorf("%s is not text (%s)", u, ctype)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBodySize))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to read %s: %w", u, err)
+	}
+	return final, body, strings.Contains(ctype, "html"), nil
+}
+
+var (
+	// htmlLink matches href and src attributes.
+	htmlLink = regexp.MustCompile(`(?i)\b(?:href|src)\s*=\s*["']?([^"'\s>]+)`)
+	// cssLink matches url() and @import references in stylesheets and
+	// inline styles.
+	cssLink = regexp.MustCompile(`(?i)(?:url\(\s*["']?([^"')\s]+)|@import\s+["']([^"']+))`)
+)
+
+// pageLinks returns the absolute URLs a page links to, without fragments.
+func pageLinks(base *url.URL, body []byte, html bool) []*url.URL {
+	var raw []string
+	if html {
+		for _, m := range htmlLink.FindAllSubmatch(body, -1) {
+			raw = append(raw, string(m[1]))
+		}
+	}
+	for _, m := range cssLink.FindAllSubmatch(body, -1) {
+		raw = append(raw, string(m[1])+string(m[2]))
+	}
+
+	var links []*url.URL
+	for _, r := range raw {
+		u, err := base.Parse(strings.ReplaceAll(r, "&amp;", "&"))
+		if err != nil {
+			continue
+		}
+		u.Fragment = ""
+		links = append(links, u)
+	}
+	return links
+}
+
+// skipExts are linked resources that never contain text worth scanning.
+var skipExts = map[string]struct{}{
+	".png": {}, ".jpg": {}, ".jpeg": {}, ".gif": {}, ".webp": {}, ".ico": {}, ".bmp": {},
+	".woff": {}, ".woff2": {}, ".ttf": {}, ".eot": {}, ".otf": {},
+	".mp3": {}, ".mp4": {}, ".webm": {}, ".avi": {}, ".mov": {},
+	".pdf": {}, ".zip": {}, ".gz": {}, ".tar": {}, ".exe": {}, ".dmg": {},
+}
+
+// crawlable reports whether a link is worth fetching.
+func crawlable(u *url.URL) bool {
+	_, skip := skipExts[strings.ToLower(path.Ext(u.Path))]
+	return !skip
+}
+
+// sameOrigin reports whether u has the scheme and host of origin.
+func sameOrigin(u, origin *url.URL) bool {
+	return u.Scheme == origin.Scheme && strings.EqualFold(u.Host, origin.Host)
+}
+
+// pageKey identifies a page for deduplication.
+func pageKey(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	return p + "?" + u.RawQuery
+}
+
+// robots returns the paths robots.txt disallows for s3finder, or for all
+// agents when it has no group of its own. A missing robots.txt allows
+// everything.
+func (c *Crawler) robots(ctx context.Context, origin *url.URL) []string {
+	u := &url.URL{Scheme: origin.Scheme, Host: origin.Host, Path: "/robots.txt"}
+	_, body, _, err := c.fetch(ctx, u, origin)
+	if err != nil {
+		return nil
+	}
+	return parseRobots(string(body), "s3finder")
+}
+
+// parseRobots returns the Disallow paths of the group for agent, falling
+// back to the "*" group. Allow rules are not supported.
+func parseRobots(body, agent string) []string {
+	groups := make(map[string][]string)
+	var agents []string
+	inRules := false
+
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive User-agent lines share one group
+			if inRules {
+				agents = nil
+				inRules = false
+			}
+			agents = append(agents, strings.ToLower(value))
+			for _, a := range agents {
+				if _, ok := groups[a]; !ok {
+					groups[a] = nil
+				}
+			}
+		case "disallow", "allow":
+			inRules = true
+			if key == "allow" || value == "" {
+				continue
+			}
+			for _, a := range agents {
+				groups[a] = append(groups[a], value)
+			}
+		}
+	}
+
+	if rules, ok := groups[strings.ToLower(agent)]; ok {
+		return rules
+	}
+	return groups["*"]
+}
+
+// isDisallowed reports whether u matches one of the disallowed path
+// prefixes.
+func isDisallowed(disallowed []string, u *url.URL) bool {
+	p := u.EscapedPath()
+	for _, prefix := range disallowed {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
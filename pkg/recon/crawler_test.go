@@ -0,0 +1,279 @@
+package recon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xeloxa/s3finder/pkg/extract"
+)
+
+// testSite serves a small website and records the paths requested.
+func testSite(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	pages := map[string]struct{ ctype, body string }{
+		"/": {"text/html", `<html><head>
+<link rel="stylesheet" href="/static/site.css">
+<script src="/static/app.js"></script>
+</head><body>
+<a href="/about.html#team">About</a>
+<a href="/private/admin.html">Admin</a>
+<a href="https://other.example/page.html">Elsewhere</a>
+<img src="https://acme-images.s3.amazonaws.com/logo.png">
+<img src="/static/logo.png">
+</body></html>`},
+		"/about.html":         {"text/html", `<a href="/deep.html">Deep</a>` + "\n" + `<p>s3://acme-about</p>`},
+		"/deep.html":          {"text/html", `<p>https://s3.eu-west-1.amazonaws.com/acme-deep/x</p>`},
+		"/private/admin.html": {"text/html", `<p>arn:aws:s3:::acme-private</p>`},
+		"/static/app.js":      {"application/javascript", "const a = 1;\nconst b = 'https://acme-js.s3.us-east-1.amazonaws.com/';\n"},
+		"/static/site.css":    {"text/css", `body { background: url("https://acme-css.s3.amazonaws.com/bg.jpg"); }`},
+		"/static/logo.png":    {"image/png", "https://acme-png.s3.amazonaws.com/"},
+		"/robots.txt":         {"text/plain", "User-agent: *\nDisallow: /private/\n"},
+	}
+
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", page.ctype)
+		w.Write([]byte(page.body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(requested)
+	}
+}
+
+func bucketsOf(refs []extract.Reference) []string {
+	var buckets []string
+	for _, r := range refs {
+		if !slices.Contains(buckets, r.Bucket) {
+			buckets = append(buckets, r.Bucket)
+		}
+	}
+	slices.Sort(buckets)
+	return buckets
+}
+
+func TestCrawler_Buckets(t *testing.T) {
+	server, requested := testSite(t)
+	c := NewCrawler(&CrawlerConfig{Timeout: time.Second, MaxDepth: 2})
+
+	refs, err := c.Buckets(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+
+	expected := []string{"acme-about", "acme-css", "acme-deep", "acme-images", "acme-js", "acme-private"}
+	if got := bucketsOf(refs); !slices.Equal(got, expected) {
+		t.Errorf("Buckets() = %v, want %v", got, expected)
+	}
+
+	for _, r := range refs {
+		if r.Bucket == "acme-js" && r.Location() != server.URL+"/static/app.js:2" {
+			t.Errorf("acme-js location = %q, want %q", r.Location(), server.URL+"/static/app.js:2")
+		}
+	}
+
+	for _, p := range requested() {
+		if p == "/static/logo.png" || p == "/robots.txt" {
+			t.Errorf("unexpected request for %s", p)
+		}
+	}
+}
+
+func TestCrawler_Depth(t *testing.T) {
+	server, _ := testSite(t)
+
+	tests := []struct {
+		depth    int
+		expected []string
+	}{
+		{0, []string{"acme-images"}},
+		{1, []string{"acme-about", "acme-css", "acme-images", "acme-js", "acme-private"}},
+	}
+
+	for _, tt := range tests {
+		c := NewCrawler(&CrawlerConfig{Timeout: time.Second, MaxDepth: tt.depth})
+		refs, err := c.Buckets(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("Buckets() error = %v", err)
+		}
+		if got := bucketsOf(refs); !slices.Equal(got, tt.expected) {
+			t.Errorf("depth %d: Buckets() = %v, want %v", tt.depth, got, tt.expected)
+		}
+	}
+}
+
+func TestCrawler_MaxPages(t *testing.T) {
+	server, requested := testSite(t)
+	c := NewCrawler(&CrawlerConfig{Timeout: time.Second, MaxDepth: 5, MaxPages: 2})
+
+	if _, err := c.Buckets(context.Background(), server.URL); err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+	if got := requested(); len(got) != 2 {
+		t.Errorf("requested %v, want 2 pages", got)
+	}
+}
+
+func TestCrawler_Robots(t *testing.T) {
+	server, requested := testSite(t)
+	c := NewCrawler(&CrawlerConfig{Timeout: time.Second, MaxDepth: 2, RespectRobots: true})
+
+	refs, err := c.Buckets(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+	if slices.Contains(bucketsOf(refs), "acme-private") {
+		t.Error("Buckets() returned a reference from a disallowed page")
+	}
+	for _, p := range requested() {
+		if strings.HasPrefix(p, "/private/") {
+			t.Errorf("disallowed path %s was fetched", p)
+		}
+	}
+}
+
+func TestCrawler_StartFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := NewCrawler(&CrawlerConfig{Timeout: time.Second})
+	if _, err := c.Buckets(context.Background(), server.URL); err == nil {
+		t.Error("Buckets() expected error for a forbidden start page")
+	}
+}
+
+func TestCrawler_StartRedirect(t *testing.T) {
+	site, requested := testSite(t)
+	var hits []string
+	entry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, r.URL.Path)
+		http.Redirect(w, r, site.URL+r.URL.Path, http.StatusMovedPermanently)
+	}))
+	defer entry.Close()
+
+	c := NewCrawler(&CrawlerConfig{Timeout: time.Second, MaxDepth: 2, RespectRobots: true})
+	refs, err := c.Buckets(context.Background(), entry.URL)
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+
+	expected := []string{"acme-about", "acme-css", "acme-deep", "acme-images", "acme-js"}
+	if got := bucketsOf(refs); !slices.Equal(got, expected) {
+		t.Errorf("Buckets() = %v, want %v", got, expected)
+	}
+	if !slices.Equal(hits, []string{"/"}) {
+		t.Errorf("redirecting origin got %v, want only the start page", hits)
+	}
+	if !slices.Contains(requested(), "/robots.txt") {
+		t.Error("robots.txt of the redirected origin was not fetched")
+	}
+}
+
+func TestCrawler_OffOriginRedirect(t *testing.T) {
+	var hits []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, r.URL.Path)
+		w.Write([]byte("s3://acme-other"))
+	}))
+	defer other.Close()
+
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<a href="/out">Out</a> <a href="/in">In</a>`))
+		case "/out":
+			http.Redirect(w, r, other.URL+"/leak", http.StatusFound)
+		case "/in":
+			w.Write([]byte("s3://acme-in"))
+		}
+	}))
+	defer site.Close()
+
+	c := NewCrawler(&CrawlerConfig{Timeout: time.Second, MaxDepth: 1})
+	refs, err := c.Buckets(context.Background(), site.URL)
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+	if got := bucketsOf(refs); !slices.Equal(got, []string{"acme-in"}) {
+		t.Errorf("Buckets() = %v, want [acme-in]", got)
+	}
+	if len(hits) != 0 {
+		t.Errorf("off-origin redirect was followed to %v", hits)
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	body := `# comment
+User-agent: Googlebot
+Disallow: /google/
+
+User-agent: s3finder
+User-agent: other
+Disallow: /mine/
+Allow: /mine/public/
+
+User-agent: *
+Disallow: /all/
+Disallow:
+`
+	tests := []struct {
+		agent    string
+		expected []string
+	}{
+		{"s3finder", []string{"/mine/"}},
+		{"other", []string{"/mine/"}},
+		{"curl", []string{"/all/"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseRobots(body, tt.agent); !slices.Equal(got, tt.expected) {
+			t.Errorf("parseRobots(%q) = %v, want %v", tt.agent, got, tt.expected)
+		}
+	}
+}
+
+func TestStartURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"acme.com", "https://acme.com/", false},
+		{"http://acme.com/blog", "http://acme.com/blog", false},
+		{"ftp://acme.com", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := startURL(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("startURL(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.expected {
+			t.Errorf("startURL(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -3,9 +3,13 @@ package recon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -24,98 +28,330 @@ type CTResult struct {
 	SerialNumber   string `json:"serial_number"`
 }
 
-// CTClient queries Certificate Transparency logs.
+// CTConfig configures a crt.sh client.
+type CTConfig struct {
+	Timeout        time.Duration
+	MaxResults     int
+	Retries        int           // Extra attempts after a failed request
+	Backoff        time.Duration // Delay before the first retry, doubled each time
+	ExcludeExpired bool          // Skip certificates that are no longer valid
+	CacheDir       string        // Directory for cached results ("" = no cache)
+	CacheTTL       time.Duration // How long cached results are used
+}
+
+// CTClient queries Certificate Transparency logs through crt.sh.
 type CTClient struct {
 	httpClient *http.Client
 	maxResults int
+	baseURL    string
+
+	retries        int
+	backoff        time.Duration
+	excludeExpired bool
+	cacheDir       string
+	cacheTTL       time.Duration
+	now            func() time.Time
 }
 
-// NewCTClient creates a new CT logs client.
+// NewCTClient creates a new CT logs client with retries and no cache.
 func NewCTClient(timeout time.Duration, maxResults int) *CTClient {
+	return NewCTClientWithConfig(&CTConfig{
+		Timeout:    timeout,
+		MaxResults: maxResults,
+		Retries:    3,
+		Backoff:    2 * time.Second,
+	})
+}
+
+// NewCTClientWithConfig creates a CT logs client from cfg.
+func NewCTClientWithConfig(cfg *CTConfig) *CTClient {
 	return &CTClient{
-		httpClient: &http.Client{Timeout: timeout},
-		maxResults: maxResults,
+		httpClient:     &http.Client{Timeout: cfg.Timeout},
+		maxResults:     cfg.MaxResults,
+		baseURL:        "https://crt.sh",
+		retries:        cfg.Retries,
+		backoff:        cfg.Backoff,
+		excludeExpired: cfg.ExcludeExpired,
+		cacheDir:       cfg.CacheDir,
+		cacheTTL:       cfg.CacheTTL,
+		now:            time.Now,
 	}
 }
 
-// FetchSubdomains queries crt.sh for subdomains of the given domain.
+// Name implements Source.
+func (c *CTClient) Name() string {
+	return "crt.sh"
+}
+
+// Subdomains implements Source.
+func (c *CTClient) Subdomains(ctx context.Context, domain string) ([]string, error) {
+	return c.FetchSubdomains(ctx, domain)
+}
+
+// FetchSubdomains queries crt.sh for subdomains of the given domain. Results
+// are served from the cache while fresh. Failed requests, 429s and 5xx
+// responses are retried with exponential backoff.
 func (c *CTClient) FetchSubdomains(ctx context.Context, domain string) ([]string, error) {
 	domain = cleanDomain(domain)
 	if domain == "" {
 		return nil, fmt.Errorf("invalid domain")
 	}
 
-	apiURL := fmt.Sprintf("https://crt.sh/?q=%%25.%s&output=json", url.QueryEscape(domain))
+	if subs, ok := c.loadCache(domain); ok {
+		return subs, nil
+	}
+
+	var lastErr error
+	delay := c.backoff
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		subs, complete, err := c.fetch(ctx, domain)
+		if err == nil {
+			c.saveCache(domain, subs, complete)
+			return subs, nil
+		}
+		lastErr = err
+		if !isRetryable(err) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// fetch performs one crt.sh request. complete is false if the results were
+// cut off at maxResults.
+func (c *CTClient) fetch(ctx context.Context, domain string) (subs []string, complete bool, err error) {
+	apiURL := fmt.Sprintf("%s/?q=%%25.%s&output=json", c.baseURL, url.QueryEscape(domain))
+	if c.excludeExpired {
+		apiURL += "&exclude=expired"
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("User-Agent", "s3finder/1.0")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("crt.sh request failed: %w", err)
+		return nil, false, &retryableError{fmt.Errorf("crt.sh request failed: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("crt.sh returned status %d", resp.StatusCode)
+		err := fmt.Errorf("crt.sh returned status %d", resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, false, &retryableError{err}
+		}
+		return nil, false, err
 	}
 
-	var results []CTResult
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		return nil, fmt.Errorf("failed to parse crt.sh response: %w", err)
+	subs, complete, err = c.decode(resp.Body, domain)
+	if err != nil {
+		return nil, false, &retryableError{fmt.Errorf("failed to parse crt.sh response: %w", err)}
+	}
+	return subs, complete, nil
+}
+
+// decode reads the crt.sh JSON array one entry at a time, so large responses
+// are never held in memory and reading stops once maxResults is reached.
+func (c *CTClient) decode(r io.Reader, domain string) ([]string, bool, error) {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return nil, false, err
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, false, fmt.Errorf("expected JSON array")
 	}
 
-	return c.extractSubdomains(results, domain), nil
+	col := c.newCollector(domain)
+	for dec.More() {
+		var entry CTResult
+		if err := dec.Decode(&entry); err != nil {
+			return nil, false, err
+		}
+		if !col.add(entry) {
+			return col.subdomains, false, nil
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, false, err
+	}
+
+	return col.subdomains, true, nil
 }
 
 // extractSubdomains deduplicates and filters subdomains from CT results.
 func (c *CTClient) extractSubdomains(results []CTResult, baseDomain string) []string {
-	seen := make(map[string]struct{})
-	var subdomains []string
-
+	col := c.newCollector(baseDomain)
 	for _, r := range results {
-		names := strings.Split(r.NameValue, "\n")
-		for _, name := range names {
-			name = strings.TrimSpace(strings.ToLower(name))
-			name = strings.TrimPrefix(name, "*.")
+		if !col.add(r) {
+			break
+		}
+	}
+	return col.subdomains
+}
 
-			if name == "" || name == baseDomain {
-				continue
-			}
-			if !strings.HasSuffix(name, "."+baseDomain) && name != baseDomain {
-				continue
-			}
-			if _, ok := seen[name]; ok {
-				continue
-			}
+// ctCollector accumulates unique subdomains from CT entries.
+type ctCollector struct {
+	client     *CTClient
+	domain     string
+	seen       map[string]struct{}
+	subdomains []string
+}
 
-			seen[name] = struct{}{}
-			subdomains = append(subdomains, name)
+func (c *CTClient) newCollector(domain string) *ctCollector {
+	return &ctCollector{client: c, domain: domain, seen: make(map[string]struct{})}
+}
 
-			if len(subdomains) >= c.maxResults {
-				return subdomains
-			}
+// add records the names of an entry. It returns false once maxResults
+// subdomains were collected.
+func (col *ctCollector) add(r CTResult) bool {
+	if col.client.excludeExpired && col.client.expired(r) {
+		return true
+	}
+
+	for _, name := range strings.Split(r.NameValue, "\n") {
+		name, ok := normalizeSubdomain(name, col.domain)
+		if !ok {
+			continue
+		}
+		if _, ok := col.seen[name]; ok {
+			continue
+		}
+
+		col.seen[name] = struct{}{}
+		col.subdomains = append(col.subdomains, name)
+
+		if col.client.maxResults > 0 && len(col.subdomains) >= col.client.maxResults {
+			return false
+		}
+	}
+	return true
+}
+
+// expired reports whether the certificate's not_after lies in the past.
+// Entries without a parseable date are kept.
+func (c *CTClient) expired(r CTResult) bool {
+	notAfter, err := time.Parse("2006-01-02T15:04:05", r.NotAfter)
+	if err != nil {
+		return false
+	}
+	return notAfter.Before(c.now())
+}
+
+// retryableError marks failures worth another attempt.
+type retryableError struct{ err error }
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func isRetryable(err error) bool {
+	var re *retryableError
+	return errors.As(err, &re)
+}
+
+// ctCacheEntry is the on-disk cache format.
+type ctCacheEntry struct {
+	Domain     string    `json:"domain"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	Complete   bool      `json:"complete"` // False if cut off at the result limit
+	Subdomains []string  `json:"subdomains"`
+}
+
+// cachePath returns the cache file for domain.
+func (c *CTClient) cachePath(domain string) string {
+	name := "crtsh-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
 		}
+		return '_'
+	}, domain)
+	if c.excludeExpired {
+		name += "-valid"
+	}
+	return filepath.Join(c.cacheDir, name+".json")
+}
+
+// loadCache returns cached subdomains if they are fresh and cover
+// maxResults.
+func (c *CTClient) loadCache(domain string) ([]string, bool) {
+	if c.cacheDir == "" || c.cacheTTL <= 0 {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.cachePath(domain))
+	if err != nil {
+		return nil, false
+	}
+	var entry ctCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Domain != domain {
+		return nil, false
+	}
+	if c.now().Sub(entry.FetchedAt) > c.cacheTTL {
+		return nil, false
+	}
+	if !entry.Complete && (c.maxResults <= 0 || len(entry.Subdomains) < c.maxResults) {
+		return nil, false
+	}
+
+	subs := entry.Subdomains
+	if c.maxResults > 0 && len(subs) > c.maxResults {
+		subs = subs[:c.maxResults]
+	}
+	return subs, true
+}
+
+// saveCache writes results to the cache. Failures are ignored; the cache
+// is only an optimization.
+func (c *CTClient) saveCache(domain string, subs []string, complete bool) {
+	if c.cacheDir == "" || c.cacheTTL <= 0 {
+		return
 	}
 
-	return subdomains
+	data, err := json.Marshal(ctCacheEntry{
+		Domain:     domain,
+		FetchedAt:  c.now(),
+		Complete:   complete,
+		Subdomains: subs,
+	})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.cacheDir, 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.cachePath(domain), data, 0644)
 }
 
-// SubdomainsToSeeds converts subdomains to potential S3 bucket seed names.
+// SubdomainsToSeeds converts subdomains to potential S3 bucket seed names:
+// dev-api.acme.com gives dev-api, dev-api-acme and acme-dev-api. When
+// baseDomain is empty or does not match, each subdomain's registrable
+// domain is used. Subdomains made only of stopwords are skipped.
 func SubdomainsToSeeds(subdomains []string, baseDomain string) []string {
 	seen := make(map[string]struct{})
 	var seeds []string
 
 	baseDomain = cleanDomain(baseDomain)
-	baseParts := strings.Split(baseDomain, ".")
-	basePrefix := baseParts[0]
 
 	for _, sub := range subdomains {
-		sub = strings.TrimSuffix(sub, "."+baseDomain)
-		if sub == "" || sub == baseDomain {
+		sub = strings.ToLower(sub)
+		base := baseDomain
+		if base == "" || !strings.HasSuffix(sub, "."+base) {
+			base = BaseDomain(sub)
+		}
+		basePrefix, _, _ := strings.Cut(base, ".")
+
+		sub = strings.TrimSuffix(sub, "."+base)
+		if sub == "" || sub == base || onlyStopwords(sub) {
 			continue
 		}
 
@@ -131,6 +367,19 @@ func SubdomainsToSeeds(subdomains []string, baseDomain string) []string {
 	return seeds
 }
 
+// onlyStopwords reports whether every label of a subdomain part is a
+// stopword, ignoring trailing digits (mail2, ns1.dns).
+func onlyStopwords(sub string) bool {
+	for _, token := range strings.FieldsFunc(sub, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	}) {
+		if !IsStopword(strings.TrimRight(token, "0123456789")) {
+			return false
+		}
+	}
+	return true
+}
+
 // generateSeedCandidates creates potential bucket names from a subdomain part.
 func generateSeedCandidates(subPart, basePrefix string) []string {
 	var candidates []string
@@ -1,8 +1,11 @@
 package recon
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -78,6 +81,17 @@ func TestSubdomainsToSeeds(t *testing.T) {
 	}
 }
 
+func TestSubdomainsToSeeds_Stopwords(t *testing.T) {
+	subdomains := []string{"www.acme.com", "mail2.acme.com", "ns1.dns.acme.com", "mail.billing.acme.com", "dev.globex.co.uk"}
+
+	seeds := SubdomainsToSeeds(subdomains, "")
+
+	expected := []string{"mail-billing", "mail-billing-acme", "acme-mail-billing", "dev", "dev-globex", "globex-dev"}
+	if !slices.Equal(seeds, expected) {
+		t.Errorf("SubdomainsToSeeds() = %v, want %v", seeds, expected)
+	}
+}
+
 func TestGenerateSeedCandidates(t *testing.T) {
 	candidates := generateSeedCandidates("dev", "example")
 
@@ -148,3 +162,169 @@ func TestCTClientMaxResults(t *testing.T) {
 		t.Errorf("Expected max 2 results, got %d", len(results))
 	}
 }
+
+// newTestCTClient returns a client for server with fast retries.
+func newTestCTClient(server *httptest.Server, cfg CTConfig) *CTClient {
+	cfg.Timeout = 5 * time.Second
+	if cfg.MaxResults == 0 {
+		cfg.MaxResults = 100
+	}
+	c := NewCTClientWithConfig(&cfg)
+	c.baseURL = server.URL
+	return c
+}
+
+func TestCTClient_Retry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"name_value": "dev.example.com"}]`))
+	}))
+	defer server.Close()
+
+	c := newTestCTClient(server, CTConfig{Retries: 3, Backoff: time.Millisecond})
+	subs, err := c.FetchSubdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("FetchSubdomains() error = %v", err)
+	}
+	if len(subs) != 1 || requests.Load() != 3 {
+		t.Errorf("FetchSubdomains() = %v after %d requests, want 1 subdomain after 3", subs, requests.Load())
+	}
+}
+
+func TestCTClient_RetryExhausted(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := newTestCTClient(server, CTConfig{Retries: 2, Backoff: time.Millisecond})
+	if _, err := c.FetchSubdomains(context.Background(), "example.com"); err == nil {
+		t.Error("FetchSubdomains() error = nil, want error")
+	}
+	if requests.Load() != 3 {
+		t.Errorf("made %d requests, want 3", requests.Load())
+	}
+}
+
+func TestCTClient_NoRetryOnClientError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := newTestCTClient(server, CTConfig{Retries: 3, Backoff: time.Millisecond})
+	if _, err := c.FetchSubdomains(context.Background(), "example.com"); err == nil {
+		t.Error("FetchSubdomains() error = nil, want error")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("made %d requests, want 1", requests.Load())
+	}
+}
+
+func TestCTClient_StreamingStopsAtLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response is cut off after the first entries; streaming must
+		// stop before reaching the broken part.
+		w.Write([]byte(`[{"name_value": "a.example.com"}, {"name_value": "b.example.com"}, {"name_value": "c.exa`))
+	}))
+	defer server.Close()
+
+	c := newTestCTClient(server, CTConfig{MaxResults: 2})
+	subs, err := c.FetchSubdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("FetchSubdomains() error = %v", err)
+	}
+	if want := []string{"a.example.com", "b.example.com"}; !slices.Equal(subs, want) {
+		t.Errorf("FetchSubdomains() = %v, want %v", subs, want)
+	}
+}
+
+func TestCTClient_ExcludeExpired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("exclude") != "expired" {
+			t.Errorf("exclude = %q, want expired", r.URL.Query().Get("exclude"))
+		}
+		w.Write([]byte(`[
+			{"name_value": "old.example.com", "not_after": "2020-01-01T00:00:00"},
+			{"name_value": "new.example.com", "not_after": "2030-01-01T00:00:00"},
+			{"name_value": "unknown.example.com"}
+		]`))
+	}))
+	defer server.Close()
+
+	c := newTestCTClient(server, CTConfig{ExcludeExpired: true})
+	c.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	subs, err := c.FetchSubdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("FetchSubdomains() error = %v", err)
+	}
+	if want := []string{"new.example.com", "unknown.example.com"}; !slices.Equal(subs, want) {
+		t.Errorf("FetchSubdomains() = %v, want %v", subs, want)
+	}
+}
+
+func TestCTClient_Cache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`[{"name_value": "dev.example.com\napi.example.com"}]`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newClient := func() *CTClient {
+		c := newTestCTClient(server, CTConfig{CacheDir: dir, CacheTTL: time.Hour})
+		c.now = func() time.Time { return now }
+		return c
+	}
+
+	for i := 0; i < 2; i++ {
+		subs, err := newClient().FetchSubdomains(context.Background(), "example.com")
+		if err != nil || len(subs) != 2 {
+			t.Fatalf("FetchSubdomains() = %v, %v", subs, err)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("made %d requests within the TTL, want 1", requests.Load())
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := newClient().FetchSubdomains(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("made %d requests after the TTL expired, want 2", requests.Load())
+	}
+}
+
+func TestCTClient_CacheIncomplete(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name_value": "a.example.com\nb.example.com\nc.example.com"}]`))
+	}))
+	defer server.Close()
+
+	small := newTestCTClient(server, CTConfig{MaxResults: 1, CacheDir: dir, CacheTTL: time.Hour})
+	if _, err := small.FetchSubdomains(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A truncated cache entry cannot satisfy a larger limit
+	large := newTestCTClient(server, CTConfig{MaxResults: 10, CacheDir: dir, CacheTTL: time.Hour})
+	if _, ok := large.loadCache("example.com"); ok {
+		t.Error("loadCache() used a truncated entry for a larger limit")
+	}
+	if _, ok := small.loadCache("example.com"); !ok {
+		t.Error("loadCache() ignored a fresh entry")
+	}
+}
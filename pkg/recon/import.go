@@ -0,0 +1,126 @@
+package recon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// LoadSubdomains reads hosts from the output of a recon tool. The format is
+// detected per line, so concatenated outputs work too:
+//
+//   - plain lists, one host or URL per line (CSV lines use the first column)
+//   - amass JSON lines ({"name": "dev.example.com", ...})
+//   - subfinder JSONL ({"host": "dev.example.com", ...})
+//   - massdns simple output (dev.example.com. A 192.0.2.1) and its JSON
+//     output ({"name": "dev.example.com.", ...})
+//
+// Hosts are lowercased and deduplicated in file order.
+func LoadSubdomains(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	seen := make(map[string]struct{})
+	var hosts []string
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		host, err := parseHostLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		host = normalizeHost(host)
+		if host == "" || !strings.Contains(host, ".") || net.ParseIP(host) != nil {
+			continue
+		}
+		if _, ok := seen[host]; !ok {
+			seen[host] = struct{}{}
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts, sc.Err()
+}
+
+// parseHostLine extracts the host from one line of tool output.
+func parseHostLine(line string) (string, error) {
+	if strings.HasPrefix(line, "{") {
+		var record struct {
+			Name string `json:"name"` // amass, massdns
+			Host string `json:"host"` // subfinder
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return "", fmt.Errorf("invalid JSON: %w", err)
+		}
+		if record.Host != "" {
+			return record.Host, nil
+		}
+		return record.Name, nil
+	}
+
+	// URLs from crawlers or httpx
+	if strings.Contains(line, "://") {
+		u, err := url.Parse(line)
+		if err != nil {
+			return "", nil
+		}
+		return u.Hostname(), nil
+	}
+
+	// massdns "name. TYPE data", CSV "host,source" and plain lists
+	field := strings.Fields(line)[0]
+	field, _, _ = strings.Cut(field, ",")
+	return field, nil
+}
+
+// normalizeHost lowercases a host and strips wildcards, trailing dots and
+// ports. It returns "" for anything that is not a hostname.
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(host, "*.")
+	host = strings.TrimSuffix(host, ".")
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	for _, r := range host {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return ""
+		}
+	}
+	return host
+}
+
+// multiPartSuffixes are second-level labels under country TLDs that belong
+// to the public suffix (example.co.uk).
+var multiPartSuffixes = map[string]struct{}{
+	"co": {}, "com": {}, "org": {}, "net": {}, "ac": {}, "gov": {}, "edu": {}, "ne": {}, "or": {},
+}
+
+// BaseDomain returns the registrable domain of host using a small built-in
+// suffix list: dev.acme.com -> acme.com, api.acme.co.uk -> acme.co.uk.
+func BaseDomain(host string) string {
+	labels := strings.Split(normalizeHost(host), ".")
+	n := 2
+	if len(labels) >= 3 && len(labels[len(labels)-1]) == 2 {
+		if _, ok := multiPartSuffixes[labels[len(labels)-2]]; ok {
+			n = 3
+		}
+	}
+	if len(labels) <= n {
+		return strings.Join(labels, ".")
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
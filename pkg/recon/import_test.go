@@ -0,0 +1,101 @@
+package recon
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadSubdomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			"plain list",
+			"dev.example.com\n\n# comment\nAPI.example.com\n*.cdn.example.com\ndev.example.com\n",
+			[]string{"dev.example.com", "api.example.com", "cdn.example.com"},
+		},
+		{
+			"amass json",
+			`{"name":"dev.example.com","domain":"example.com","addresses":[{"ip":"192.0.2.1"}],"tag":"cert","sources":["crtsh"]}
+{"name":"vpn.example.com","domain":"example.com","addresses":[],"tag":"dns","sources":["DNS"]}`,
+			[]string{"dev.example.com", "vpn.example.com"},
+		},
+		{
+			"subfinder jsonl",
+			`{"host":"dev.example.com","input":"example.com","source":"crtsh"}
+{"host":"mail.example.com","input":"example.com","source":"alienvault"}`,
+			[]string{"dev.example.com", "mail.example.com"},
+		},
+		{
+			"massdns simple",
+			"dev.example.com. A 192.0.2.1\nassets.example.com. CNAME assets.s3.amazonaws.com.\ndev.example.com. A 192.0.2.2\n",
+			[]string{"dev.example.com", "assets.example.com"},
+		},
+		{
+			"massdns json",
+			`{"name":"dev.example.com.","type":"A","class":"IN","status":"NOERROR","data":{"answers":[{"ttl":300,"type":"A","class":"IN","name":"dev.example.com.","data":"192.0.2.1"}]}}`,
+			[]string{"dev.example.com"},
+		},
+		{
+			"urls, csv and noise",
+			"https://app.example.com:8443/login\nshop.example.com,crtsh\n192.0.2.1\nlocalhost\nbad host!.example.com\n",
+			[]string{"app.example.com", "shop.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "subs.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadSubdomains(path)
+			if err != nil {
+				t.Fatalf("LoadSubdomains() error = %v", err)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("LoadSubdomains() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadSubdomains_Errors(t *testing.T) {
+	if _, err := LoadSubdomains("/nonexistent/subs.txt"); err == nil {
+		t.Error("LoadSubdomains() error = nil, want error for missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(path, []byte("{\"host\": \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSubdomains(path); err == nil {
+		t.Error("LoadSubdomains() error = nil, want error for invalid JSON")
+	}
+}
+
+func TestBaseDomain(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{"dev.example.com", "example.com"},
+		{"a.b.example.com", "example.com"},
+		{"example.com", "example.com"},
+		{"api.example.co.uk", "example.co.uk"},
+		{"api.example.com.au", "example.com.au"},
+		{"dev.example.io", "example.io"},
+		{"DEV.Example.com.", "example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := BaseDomain(tt.host); got != tt.expected {
+			t.Errorf("BaseDomain(%q) = %q, want %q", tt.host, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xeloxa/s3finder/pkg/extract"
+)
+
+// Source discovers subdomains of a domain.
+type Source interface {
+	// Name identifies the source in reports.
+	Name() string
+	// Subdomains returns the subdomains of domain known to the source.
+	Subdomains(ctx context.Context, domain string) ([]string, error)
+}
+
+// BucketSource finds bucket references published by a target, such as the
+// pages of its website.
+type BucketSource interface {
+	// Name identifies the source in reports.
+	Name() string
+	// Buckets returns the bucket references found for target. Reference.File
+	// holds the URL the reference was found at.
+	Buckets(ctx context.Context, target string) ([]extract.Reference, error)
+}
+
+// SourceResult is the outcome of one source in a Gather run.
+type SourceResult struct {
+	Source   string
+	Count    int // Subdomains returned, before merging
+	Err      error
+	Duration time.Duration
+}
+
+// Aggregator queries several sources in parallel and merges their results.
+type Aggregator struct {
+	sources    []Source
+	timeout    time.Duration
+	maxResults int
+}
+
+// NewAggregator creates an aggregator. Each source gets its own timeout, so
+// a slow provider cannot hold up the others. maxResults caps the merged
+// list (0 = unlimited).
+func NewAggregator(timeout time.Duration, maxResults int, sources ...Source) *Aggregator {
+	return &Aggregator{
+		sources:    sources,
+		timeout:    timeout,
+		maxResults: maxResults,
+	}
+}
+
+// FetchSubdomains queries all sources and returns the deduplicated
+// subdomains in source order, with a result per source. It fails only if
+// every source failed.
+func (a *Aggregator) FetchSubdomains(ctx context.Context, domain string) ([]string, []SourceResult, error) {
+	domain = cleanDomain(domain)
+	if domain == "" {
+		return nil, nil, fmt.Errorf("invalid domain")
+	}
+	if len(a.sources) == 0 {
+		return nil, nil, fmt.Errorf("no recon sources configured")
+	}
+
+	results := make([]SourceResult, len(a.sources))
+	found := make([][]string, len(a.sources))
+
+	var wg sync.WaitGroup
+	for i, src := range a.sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+
+			srcCtx := ctx
+			if a.timeout > 0 {
+				var cancel context.CancelFunc
+				srcCtx, cancel = context.WithTimeout(ctx, a.timeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			subs, err := src.Subdomains(srcCtx, domain)
+			results[i] = SourceResult{
+				Source:   src.Name(),
+				Count:    len(subs),
+				Err:      err,
+				Duration: time.Since(start),
+			}
+			found[i] = subs
+		}(i, src)
+	}
+	wg.Wait()
+
+	seen := make(map[string]struct{})
+	var subdomains []string
+	failed := 0
+	for i, subs := range found {
+		if results[i].Err != nil {
+			failed++
+		}
+		for _, sub := range subs {
+			sub, ok := normalizeSubdomain(sub, domain)
+			if !ok {
+				continue
+			}
+			if _, ok := seen[sub]; ok {
+				continue
+			}
+			seen[sub] = struct{}{}
+			subdomains = append(subdomains, sub)
+		}
+	}
+
+	if a.maxResults > 0 && len(subdomains) > a.maxResults {
+		subdomains = subdomains[:a.maxResults]
+	}
+	if failed == len(a.sources) {
+		return nil, results, fmt.Errorf("all recon sources failed")
+	}
+
+	return subdomains, results, nil
+}
+
+// normalizeSubdomain lowercases a certificate name, strips a wildcard label
+// and reports whether it is a proper subdomain of baseDomain.
+func normalizeSubdomain(name, baseDomain string) (string, bool) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "*."), ".")
+
+	if name == "" || name == baseDomain || !strings.HasSuffix(name, "."+baseDomain) {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,231 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeSource returns fixed subdomains after an optional delay.
+type fakeSource struct {
+	name  string
+	subs  []string
+	err   error
+	delay time.Duration
+}
+
+func (f *fakeSource) Name() string { return f.name }
+
+func (f *fakeSource) Subdomains(ctx context.Context, domain string) ([]string, error) {
+	select {
+	case <-time.After(f.delay):
+		return f.subs, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestNormalizeSubdomain(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"dev.example.com", "dev.example.com", true},
+		{"*.api.example.com", "api.example.com", true},
+		{" DEV.Example.com. ", "dev.example.com", true},
+		{"example.com", "", false},
+		{"*.example.com", "", false},
+		{"notexample.com", "", false},
+		{"dev.other.com", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeSubdomain(tt.input, "example.com")
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("normalizeSubdomain(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestAggregator_FetchSubdomains(t *testing.T) {
+	a := NewAggregator(time.Second, 0,
+		&fakeSource{name: "one", subs: []string{"dev.example.com", "api.example.com"}},
+		&fakeSource{name: "two", subs: []string{"API.example.com", "*.cdn.example.com", "evil.com"}},
+		&fakeSource{name: "broken", err: errors.New("down")},
+	)
+
+	subs, results, err := a.FetchSubdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("FetchSubdomains() error = %v", err)
+	}
+
+	want := []string{"dev.example.com", "api.example.com", "cdn.example.com"}
+	if !slices.Equal(subs, want) {
+		t.Errorf("FetchSubdomains() = %v, want %v", subs, want)
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Source] = r.Count
+	}
+	if counts["one"] != 2 || counts["two"] != 3 || counts["broken"] != 0 {
+		t.Errorf("per-source counts = %v", counts)
+	}
+	if results[2].Err == nil {
+		t.Error("broken source reported no error")
+	}
+}
+
+func TestAggregator_Timeout(t *testing.T) {
+	a := NewAggregator(50*time.Millisecond, 0,
+		&fakeSource{name: "fast", subs: []string{"dev.example.com"}},
+		&fakeSource{name: "slow", subs: []string{"slow.example.com"}, delay: 5 * time.Second},
+	)
+
+	start := time.Now()
+	subs, results, err := a.FetchSubdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("FetchSubdomains() error = %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("slow source was not cut off by its timeout")
+	}
+	if !slices.Equal(subs, []string{"dev.example.com"}) {
+		t.Errorf("FetchSubdomains() = %v", subs)
+	}
+	if !errors.Is(results[1].Err, context.DeadlineExceeded) {
+		t.Errorf("slow source error = %v, want deadline exceeded", results[1].Err)
+	}
+}
+
+func TestAggregator_Limit(t *testing.T) {
+	a := NewAggregator(time.Second, 2,
+		&fakeSource{name: "one", subs: []string{"a.example.com", "b.example.com", "c.example.com"}},
+	)
+
+	subs, _, err := a.FetchSubdomains(context.Background(), "example.com")
+	if err != nil || len(subs) != 2 {
+		t.Errorf("FetchSubdomains() = %v, %v, want 2 subdomains", subs, err)
+	}
+}
+
+func TestAggregator_AllFailed(t *testing.T) {
+	a := NewAggregator(time.Second, 0,
+		&fakeSource{name: "one", err: errors.New("down")},
+		&fakeSource{name: "two", err: errors.New("down")},
+	)
+
+	if _, _, err := a.FetchSubdomains(context.Background(), "example.com"); err == nil {
+		t.Error("FetchSubdomains() error = nil, want error when every source failed")
+	}
+	if _, _, err := NewAggregator(time.Second, 0).FetchSubdomains(context.Background(), "example.com"); err == nil {
+		t.Error("FetchSubdomains() error = nil, want error without sources")
+	}
+}
+
+func TestCTClient_Source(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "%.example.com" || r.URL.Query().Get("output") != "json" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"name_value": "dev.example.com\n*.api.example.com"}]`))
+	}))
+	defer server.Close()
+
+	c := NewCTClient(5*time.Second, 100)
+	c.baseURL = server.URL
+
+	var src Source = c
+	subs, err := src.Subdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Subdomains() error = %v", err)
+	}
+	if want := []string{"dev.example.com", "api.example.com"}; !slices.Equal(subs, want) {
+		t.Errorf("Subdomains() = %v, want %v", subs, want)
+	}
+	if src.Name() != "crt.sh" {
+		t.Errorf("Name() = %q", src.Name())
+	}
+}
+
+func TestCertSpotter_Subdomains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/issuances" || q.Get("domain") != "example.com" || q.Get("expand") != "dns_names" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch q.Get("after") {
+		case "":
+			w.Write([]byte(`[{"id":"1","dns_names":["example.com","dev.example.com"]},{"id":"2","dns_names":["*.api.example.com"]}]`))
+		case "2":
+			w.Write([]byte(`[{"id":"3","dns_names":["dev.example.com","cdn.example.com"]}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewCertSpotter(5*time.Second, "secret")
+	c.baseURL = server.URL
+
+	subs, err := c.Subdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Subdomains() error = %v", err)
+	}
+	if want := []string{"dev.example.com", "api.example.com", "cdn.example.com"}; !slices.Equal(subs, want) {
+		t.Errorf("Subdomains() = %v, want %v", subs, want)
+	}
+}
+
+func TestCertSpotter_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := NewCertSpotter(5*time.Second, "")
+	c.baseURL = server.URL
+
+	if _, err := c.Subdomains(context.Background(), "example.com"); err == nil {
+		t.Error("Subdomains() error = nil, want error on status 429")
+	}
+}
+
+func TestCensys_Subdomains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/certificates/search" || r.URL.Query().Get("q") != "names: example.com" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "id" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"result":{"hits":[{"names":["dev.example.com","*.example.com"]}],"links":{"next":"abc"}}}`))
+			return
+		}
+		w.Write([]byte(`{"result":{"hits":[{"names":["vpn.example.com"]}],"links":{"next":""}}}`))
+	}))
+	defer server.Close()
+
+	c := NewCensys(5*time.Second, "id", "secret", server.URL)
+	subs, err := c.Subdomains(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Subdomains() error = %v", err)
+	}
+	if want := []string{"dev.example.com", "vpn.example.com"}; !slices.Equal(subs, want) {
+		t.Errorf("Subdomains() = %v, want %v", subs, want)
+	}
+
+	if _, err := NewCensys(time.Second, "", "", server.URL).Subdomains(context.Background(), "example.com"); err == nil {
+		t.Error("Subdomains() without credentials error = nil, want error")
+	}
+}
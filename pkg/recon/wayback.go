@@ -0,0 +1,246 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/xeloxa/s3finder/pkg/extract"
+)
+
+// WaybackConfig configures a Wayback source.
+type WaybackConfig struct {
+	Timeout  time.Duration // Per request
+	BaseURL  string        // CDX server ("" = https://web.archive.org)
+	RPS      float64       // Requests per second (0 = 1)
+	PageSize int           // URLs per request (0 = 1000)
+	MaxPages int           // Requests per domain (0 = 10)
+}
+
+// Wayback lists archived URLs of a domain from a CDX server such as the
+// Internet Archive's and finds bucket references in them.
+type Wayback struct {
+	httpClient *http.Client
+	baseURL    string
+	limiter    *rate.Limiter
+	pageSize   int
+	maxPages   int
+	retries    int
+	backoff    time.Duration
+}
+
+// NewWayback creates a Wayback source.
+func NewWayback(cfg *WaybackConfig) *Wayback {
+	w := &Wayback{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		pageSize:   cfg.PageSize,
+		maxPages:   cfg.MaxPages,
+		retries:    3,
+		backoff:    2 * time.Second,
+	}
+	if w.baseURL == "" {
+		w.baseURL = "https://web.archive.org"
+	}
+	rps := cfg.RPS
+	if rps <= 0 {
+		rps = 1
+	}
+	w.limiter = rate.NewLimiter(rate.Limit(rps), 1)
+	if w.pageSize <= 0 {
+		w.pageSize = 1000
+	}
+	if w.maxPages <= 0 {
+		w.maxPages = 10
+	}
+	return w
+}
+
+// Name implements BucketSource.
+func (w *Wayback) Name() string {
+	return "wayback"
+}
+
+// Buckets implements BucketSource.
+func (w *Wayback) Buckets(ctx context.Context, domain string) ([]extract.Reference, error) {
+	urls, err := w.URLs(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	return URLReferences(urls), nil
+}
+
+// URLs returns the distinct archived URLs of domain and its subdomains,
+// following resume keys for up to MaxPages requests. When a later page
+// fails, the URLs collected so far are returned.
+func (w *Wayback) URLs(ctx context.Context, domain string) ([]string, error) {
+	domain = cleanDomain(domain)
+	if domain == "" {
+		return nil, fmt.Errorf("invalid domain")
+	}
+
+	var urls []string
+	resumeKey := ""
+	for page := 0; page < w.maxPages; page++ {
+		q := url.Values{}
+		q.Set("url", domain)
+		q.Set("matchType", "domain")
+		q.Set("output", "json")
+		q.Set("fl", "original")
+		q.Set("collapse", "urlkey")
+		q.Set("limit", fmt.Sprint(w.pageSize))
+		q.Set("showResumeKey", "true")
+		if resumeKey != "" {
+			q.Set("resumeKey", resumeKey)
+		}
+
+		rows, next, err := w.fetchPage(ctx, w.baseURL+"/cdx/search/cdx?"+q.Encode())
+		if err != nil {
+			if len(urls) > 0 {
+				return urls, nil
+			}
+			return nil, err
+		}
+		urls = append(urls, rows...)
+
+		if next == "" {
+			break
+		}
+		resumeKey = next
+	}
+	return urls, nil
+}
+
+// fetchPage requests one CDX page, retrying throttled and failed requests
+// with exponential backoff.
+func (w *Wayback) fetchPage(ctx context.Context, u string) ([]string, string, error) {
+	backoff := w.backoff
+	var lastErr error
+	for attempt := 0; attempt <= w.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, "", ctx.Err()
+			}
+			backoff *= 2
+		}
+		if err := w.limiter.Wait(ctx); err != nil {
+			return nil, "", err
+		}
+
+		rows, next, err := w.fetch(ctx, u)
+		if err == nil {
+			return rows, next, nil
+		}
+		lastErr = err
+		if !isRetryable(err) {
+			return nil, "", err
+		}
+	}
+	return nil, "", lastErr
+}
+
+func (w *Wayback) fetch(ctx context.Context, u string) ([]string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "s3finder/1.0")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, "", &retryableError{fmt.Errorf("wayback request failed: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, "", &retryableError{fmt.Errorf("wayback returned status %d", resp.StatusCode)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("wayback returned status %d", resp.StatusCode)
+	}
+
+	var table [][]string
+	if err := json.NewDecoder(resp.Body).Decode(&table); err != nil {
+		return nil, "", fmt.Errorf("failed to parse wayback response: %w", err)
+	}
+	rows, next := parseCDX(table)
+	return rows, next, nil
+}
+
+// parseCDX splits a JSON CDX table into URLs and the resume key. The first
+// row is the header; with showResumeKey the key follows an empty row.
+func parseCDX(table [][]string) ([]string, string) {
+	var rows []string
+	for i, row := range table {
+		if i == 0 && len(row) == 1 && row[0] == "original" {
+			continue
+		}
+		if len(row) == 0 {
+			if i+1 < len(table) && len(table[i+1]) == 1 {
+				return rows, table[i+1][0]
+			}
+			break
+		}
+		rows = append(rows, row[0])
+	}
+	return rows, ""
+}
+
+// URLReferences finds bucket references in URLs, including ones hidden in
+// escaped query parameters (?next=https%3A%2F%2Facme.s3.amazonaws.com).
+// Each reference's File is the URL it was found in.
+func URLReferences(urls []string) []extract.Reference {
+	var refs []extract.Reference
+	for _, u := range urls {
+		text := u
+		if unescaped, err := url.QueryUnescape(u); err == nil {
+			text = unescaped
+		}
+		for _, bucket := range extract.Find(text) {
+			refs = append(refs, extract.Reference{Bucket: bucket, File: u})
+		}
+	}
+	return refs
+}
+
+// pathStopwords are URL path tokens that say nothing about naming: file
+// extensions and the vocabulary of web frameworks.
+var pathStopwords = map[string]struct{}{
+	"html": {}, "htm": {}, "php": {}, "asp": {}, "aspx": {}, "jsp": {}, "cgi": {},
+	"css": {}, "png": {}, "jpg": {}, "jpeg": {}, "gif": {}, "svg": {}, "ico": {},
+	"woff": {}, "ttf": {}, "eot": {}, "txt": {}, "xml": {}, "json": {}, "pdf": {},
+	"index": {}, "default": {}, "page": {}, "pages": {}, "home": {}, "search": {},
+	"login": {}, "logout": {}, "signin": {}, "signup": {}, "register": {},
+	"wp": {}, "content": {}, "includes": {}, "themes": {}, "plugins": {}, "feed": {},
+	"tag": {}, "tags": {}, "category": {}, "author": {}, "comments": {}, "null": {},
+	"undefined": {}, "min": {}, "bundle": {}, "chunk": {}, "main": {}, "vendor": {},
+	"the": {}, "and": {}, "for": {}, "with": {},
+}
+
+// PathWords extracts words from the paths of urls for use as seeds,
+// scored by how many URLs contain them. Extensions, framework vocabulary
+// and subdomain stopwords are skipped.
+func PathWords(urls []string, opts WordOptions) []Word {
+	groups := make([][]string, 0, len(urls))
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		groups = append(groups, strings.FieldsFunc(strings.ToLower(u.Path), func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+		}))
+	}
+	return scoreTokens(groups, opts, func(token string) bool {
+		_, stop := pathStopwords[token]
+		return stop || IsStopword(token)
+	})
+}
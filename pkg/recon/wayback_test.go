@@ -0,0 +1,145 @@
+package recon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeArchive serves a CDX index in pages of two URLs.
+func fakeArchive(t *testing.T, urls []string, failFirst int) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/cdx/search/cdx" {
+			http.NotFound(w, r)
+			return
+		}
+		if requests <= failFirst {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("url") != "acme.com" || q.Get("matchType") != "domain" || q.Get("output") != "json" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+
+		start := 0
+		if key := q.Get("resumeKey"); key != "" {
+			start = int(key[0] - '0')
+		}
+		end := min(start+2, len(urls))
+
+		w.Write([]byte(`[["original"]`))
+		for _, u := range urls[start:end] {
+			w.Write([]byte(`,["` + u + `"]`))
+		}
+		if end < len(urls) {
+			w.Write([]byte(`,[],["` + string(rune('0'+end)) + `"]`))
+		}
+		w.Write([]byte(`]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+var archivedURLs = []string{
+	"https://acme.com/billing/invoices.html",
+	"https://acme.com/redirect?to=https%3A%2F%2Facme-media.s3.amazonaws.com%2Fa.png",
+	"https://cdn.acme.com/static/app.js",
+	"https://acme.com/billing/export?bucket=x",
+	"http://s3.amazonaws.com/acme-legacy/index.html",
+}
+
+func TestWayback_URLs(t *testing.T) {
+	server, requests := fakeArchive(t, archivedURLs, 0)
+	w := NewWayback(&WaybackConfig{Timeout: time.Second, BaseURL: server.URL + "/", RPS: 1000, PageSize: 2})
+
+	urls, err := w.URLs(context.Background(), "https://acme.com/")
+	if err != nil {
+		t.Fatalf("URLs() error = %v", err)
+	}
+	if !slices.Equal(urls, archivedURLs) {
+		t.Errorf("URLs() = %v, want %v", urls, archivedURLs)
+	}
+	if *requests != 3 {
+		t.Errorf("requests = %d, want 3 pages", *requests)
+	}
+}
+
+func TestWayback_MaxPages(t *testing.T) {
+	server, _ := fakeArchive(t, archivedURLs, 0)
+	w := NewWayback(&WaybackConfig{Timeout: time.Second, BaseURL: server.URL, RPS: 1000, MaxPages: 2})
+
+	urls, err := w.URLs(context.Background(), "acme.com")
+	if err != nil {
+		t.Fatalf("URLs() error = %v", err)
+	}
+	if len(urls) != 4 {
+		t.Errorf("URLs() returned %d URLs, want 4", len(urls))
+	}
+}
+
+func TestWayback_Retry(t *testing.T) {
+	server, requests := fakeArchive(t, archivedURLs, 2)
+	w := NewWayback(&WaybackConfig{Timeout: time.Second, BaseURL: server.URL, RPS: 1000})
+	w.backoff = time.Millisecond
+
+	if _, err := w.URLs(context.Background(), "acme.com"); err != nil {
+		t.Fatalf("URLs() error = %v", err)
+	}
+	if *requests != 5 {
+		t.Errorf("requests = %d, want 2 throttled + 3 pages", *requests)
+	}
+
+	w.retries = 0
+	server2, _ := fakeArchive(t, archivedURLs, 1)
+	w.baseURL = server2.URL
+	if _, err := w.URLs(context.Background(), "acme.com"); err == nil {
+		t.Error("URLs() expected error without retries")
+	}
+}
+
+func TestWayback_RateLimit(t *testing.T) {
+	server, _ := fakeArchive(t, archivedURLs, 0)
+	w := NewWayback(&WaybackConfig{Timeout: time.Second, BaseURL: server.URL, RPS: 20})
+
+	start := time.Now()
+	if _, err := w.URLs(context.Background(), "acme.com"); err != nil {
+		t.Fatalf("URLs() error = %v", err)
+	}
+	// Three requests at 20/s take at least two intervals
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("three requests took %s, want rate-limited to 20/s", elapsed)
+	}
+}
+
+func TestWayback_Buckets(t *testing.T) {
+	server, _ := fakeArchive(t, archivedURLs, 0)
+	w := NewWayback(&WaybackConfig{Timeout: time.Second, BaseURL: server.URL, RPS: 1000})
+
+	refs, err := w.Buckets(context.Background(), "acme.com")
+	if err != nil {
+		t.Fatalf("Buckets() error = %v", err)
+	}
+	if got, expected := bucketsOf(refs), []string{"acme-legacy", "acme-media"}; !slices.Equal(got, expected) {
+		t.Errorf("Buckets() = %v, want %v", got, expected)
+	}
+	for _, r := range refs {
+		if r.Bucket == "acme-media" && r.File != archivedURLs[1] {
+			t.Errorf("acme-media found in %q, want %q", r.File, archivedURLs[1])
+		}
+	}
+}
+
+func TestPathWords(t *testing.T) {
+	got := words(PathWords(archivedURLs, WordOptions{}))
+	expected := []string{"billing", "invoices", "redirect", "static", "app", "export", "acme", "legacy"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("PathWords() = %v, want %v", got, expected)
+	}
+}
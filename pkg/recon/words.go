@@ -0,0 +1,123 @@
+package recon
+
+import (
+	"sort"
+	"strings"
+)
+
+// stopwords are subdomain labels that name infrastructure rather than the
+// organization's projects. Permuting them only produces noise such as
+// autodiscover-backup.
+var stopwords = map[string]struct{}{
+	// Mail
+	"mail": {}, "webmail": {}, "smtp": {}, "imap": {}, "pop": {}, "mx": {}, "mta": {},
+	"relay": {}, "autodiscover": {}, "autoconfig": {}, "owa": {}, "exchange": {},
+	"domainkey": {}, "dkim": {}, "spf": {}, "dmarc": {}, "bounce": {}, "bounces": {},
+	// Hosting panels
+	"cpanel": {}, "whm": {}, "webdisk": {}, "cpcalendars": {}, "cpcontacts": {},
+	"plesk": {}, "webmin": {}, "directadmin": {},
+	// Network services
+	"www": {}, "ftp": {}, "sftp": {}, "dns": {}, "ns": {}, "ntp": {}, "vpn": {},
+	"sip": {}, "lyncdiscover": {}, "enterpriseregistration": {}, "enterpriseenrollment": {},
+	"msoid": {}, "localhost": {}, "host": {}, "server": {}, "srv": {}, "gateway": {},
+	"proxy": {}, "remote": {}, "web": {}, "secure": {}, "ssl": {}, "http": {}, "https": {},
+}
+
+// IsStopword reports whether a subdomain token names common infrastructure.
+func IsStopword(word string) bool {
+	_, ok := stopwords[word]
+	return ok
+}
+
+// WordOptions configures ExtractWords.
+type WordOptions struct {
+	MinLength int // Shorter tokens are dropped (0 = 3)
+	MaxLength int // Longer tokens are dropped (0 = 20)
+	MaxWords  int // Highest-scoring tokens returned (0 = unlimited)
+}
+
+// Word is a token extracted from subdomains.
+type Word struct {
+	Word  string
+	Count int     // Subdomains containing the token
+	Score float64 // Count, discounted for tokens that look generated
+}
+
+// ExtractWords splits subdomains into tokens and returns the most useful
+// ones for permutation, best first. The base domain is removed from each
+// subdomain first; when baseDomain is empty or does not match, the
+// registrable domain is used. Trailing digits are stripped (web01 -> web),
+// and stopwords, numbers and out-of-range lengths are dropped. Tokens seen
+// in more subdomains score higher; ties keep first-seen order.
+func ExtractWords(subdomains []string, baseDomain string, opts WordOptions) []Word {
+	baseDomain = cleanDomain(baseDomain)
+
+	groups := make([][]string, len(subdomains))
+	for i, sub := range subdomains {
+		groups[i] = subdomainTokens(sub, baseDomain)
+	}
+	return scoreTokens(groups, opts, IsStopword)
+}
+
+// scoreTokens counts in how many groups each token appears and returns the
+// tokens best first. Tokens are filtered as described for ExtractWords.
+func scoreTokens(groups [][]string, opts WordOptions, stop func(string) bool) []Word {
+	if opts.MinLength <= 0 {
+		opts.MinLength = 3
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = 20
+	}
+
+	index := make(map[string]int)
+	var words []Word
+	for _, tokens := range groups {
+		seen := make(map[string]struct{})
+		for _, token := range tokens {
+			token = strings.TrimRight(token, "0123456789")
+			if len(token) < opts.MinLength || len(token) > opts.MaxLength || stop(token) {
+				continue
+			}
+			if _, dup := seen[token]; dup {
+				continue
+			}
+			seen[token] = struct{}{}
+
+			if i, ok := index[token]; ok {
+				words[i].Count++
+			} else {
+				index[token] = len(words)
+				words = append(words, Word{Word: token, Count: 1})
+			}
+		}
+	}
+
+	for i := range words {
+		words[i].Score = float64(words[i].Count)
+		if strings.ContainsAny(words[i].Word, "0123456789") {
+			// Hashes and instance IDs (a1b2c3, ip10x0) are rarely reused
+			words[i].Score /= 2
+		}
+	}
+	sort.SliceStable(words, func(i, j int) bool {
+		return words[i].Score > words[j].Score
+	})
+
+	if opts.MaxWords > 0 && len(words) > opts.MaxWords {
+		words = words[:opts.MaxWords]
+	}
+	return words
+}
+
+// subdomainTokens returns the labels of sub below its base domain, split on
+// dots, dashes and underscores.
+func subdomainTokens(sub, baseDomain string) []string {
+	sub = strings.ToLower(sub)
+	base := baseDomain
+	if base == "" || !strings.HasSuffix(sub, "."+base) {
+		base = BaseDomain(sub)
+	}
+	return strings.FieldsFunc(strings.TrimSuffix(sub, "."+base), func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	})
+}
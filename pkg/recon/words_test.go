@@ -0,0 +1,72 @@
+package recon
+
+import (
+	"slices"
+	"testing"
+)
+
+func words(ws []Word) []string {
+	var out []string
+	for _, w := range ws {
+		out = append(out, w.Word)
+	}
+	return out
+}
+
+func TestExtractWords(t *testing.T) {
+	subdomains := []string{
+		"www.acme.com",
+		"mail.acme.com",
+		"autodiscover.acme.com",
+		"cpanel.acme.com",
+		"billing-api.acme.com",
+		"dev.billing.acme.com",
+		"web01.billing.acme.com",
+		"api.acme.com",
+		"a1b2c3.acme.com",
+		"2024.acme.com",
+		"verylongtokenthatisnotaword.acme.com",
+	}
+
+	got := ExtractWords(subdomains, "acme.com", WordOptions{})
+	expected := []string{"billing", "api", "dev", "a1b2c"}
+	if !slices.Equal(words(got), expected) {
+		t.Errorf("ExtractWords() = %v, want %v", words(got), expected)
+	}
+	if got[0].Count != 3 || got[0].Score != 3 {
+		t.Errorf("ExtractWords()[0] = %+v, want count and score 3", got[0])
+	}
+}
+
+func TestExtractWords_Options(t *testing.T) {
+	subdomains := []string{"qa.acme.com", "qa-eu.acme.com", "billing.acme.com", "search.acme.com"}
+
+	tests := []struct {
+		name     string
+		opts     WordOptions
+		expected []string
+	}{
+		{"defaults", WordOptions{}, []string{"billing", "search"}},
+		{"min length", WordOptions{MinLength: 2}, []string{"qa", "eu", "billing", "search"}},
+		{"max length", WordOptions{MinLength: 2, MaxLength: 6}, []string{"qa", "eu", "search"}},
+		{"max words", WordOptions{MinLength: 2, MaxWords: 2}, []string{"qa", "eu"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := words(ExtractWords(subdomains, "acme.com", tt.opts)); !slices.Equal(got, tt.expected) {
+				t.Errorf("ExtractWords() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractWords_NoBaseDomain(t *testing.T) {
+	subdomains := []string{"billing.acme.com", "search.globex.co.uk", "www.initech.io"}
+
+	got := words(ExtractWords(subdomains, "", WordOptions{}))
+	expected := []string{"billing", "search"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("ExtractWords() = %v, want %v", got, expected)
+	}
+}
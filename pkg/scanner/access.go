@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// AccessState is the anonymous access outcome reported by deep inspection.
+type AccessState int
+
+const (
+	AccessUnknown            AccessState = iota // Unclassified error or no inspection
+	AccessPublic                                // Anonymous listing succeeded
+	AccessPrivate                               // AccessDenied - listing not allowed, including Requester Pays buckets
+	AccessAllDisabled                           // AllAccessDisabled - bucket locked by AWS
+	AccessAccountProblem                        // AccountProblem - owning account suspended or unpaid
+	AccessInvalidBucketState                    // InvalidBucketState - bucket mid-transition
+	AccessACLsDisabled                          // AccessControlListNotSupported - Object Ownership enforced
+	AccessNoSuchBucket                          // NoSuchBucket - deleted since the probe
+)
+
+func (s AccessState) String() string {
+	switch s {
+	case AccessPublic:
+		return "public"
+	case AccessPrivate:
+		return "private"
+	case AccessAllDisabled:
+		return "all_access_disabled"
+	case AccessAccountProblem:
+		return "account_problem"
+	case AccessInvalidBucketState:
+		return "invalid_bucket_state"
+	case AccessACLsDisabled:
+		return "acls_disabled"
+	case AccessNoSuchBucket:
+		return "no_such_bucket"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON encodes the state by name so reports stay readable.
+func (s AccessState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a state name written by MarshalJSON.
+func (s *AccessState) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for candidate := AccessUnknown; candidate <= AccessNoSuchBucket; candidate++ {
+		if candidate.String() == name {
+			*s = candidate
+			return nil
+		}
+	}
+	*s = AccessUnknown
+	return nil
+}
+
+// ACL returns the legacy ACL label for the state.
+func (s AccessState) ACL() string {
+	switch s {
+	case AccessPublic:
+		return "public-read"
+	case AccessPrivate:
+		return "private"
+	case AccessAllDisabled:
+		return "disabled"
+	case AccessUnknown:
+		return "unknown"
+	default:
+		return s.String()
+	}
+}
+
+// accessError is the structured view of an S3 error response.
+type accessError struct {
+	state  AccessState
+	code   string
+	region string // Correct region for redirect errors, if S3 told us
+}
+
+// classifyError maps an SDK error to an AccessState using the S3 error code
+// rather than the rendered error string.
+func classifyError(err error) accessError {
+	var result accessError
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.Response != nil {
+		result.region = respErr.Response.Header.Get("x-amz-bucket-region")
+	}
+
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return result
+	}
+	result.code = apiErr.ErrorCode()
+
+	switch result.code {
+	case "AccessDenied":
+		// Requester Pays buckets answer anonymous requests with a plain
+		// AccessDenied, so they cannot be told apart from private ones
+		result.state = AccessPrivate
+	case "AllAccessDisabled":
+		result.state = AccessAllDisabled
+	case "AccountProblem":
+		result.state = AccessAccountProblem
+	case "InvalidBucketState":
+		result.state = AccessInvalidBucketState
+	case "AccessControlListNotSupported":
+		result.state = AccessACLsDisabled
+	case "NoSuchBucket":
+		result.state = AccessNoSuchBucket
+	}
+
+	return result
+}
+
+// isRedirect reports whether the error asks us to retry in another region.
+func (e accessError) isRedirect() bool {
+	switch e.code {
+	case "PermanentRedirect", "AuthorizationHeaderMalformed", "IllegalLocationConstraintException", "BucketRegionError":
+		return true
+	}
+	return false
+}
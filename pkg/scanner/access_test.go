@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func TestAccessState_String(t *testing.T) {
+	tests := []struct {
+		state    AccessState
+		expected string
+	}{
+		{AccessUnknown, "unknown"},
+		{AccessPublic, "public"},
+		{AccessPrivate, "private"},
+		{AccessAllDisabled, "all_access_disabled"},
+		{AccessAccountProblem, "account_problem"},
+		{AccessInvalidBucketState, "invalid_bucket_state"},
+		{AccessACLsDisabled, "acls_disabled"},
+		{AccessNoSuchBucket, "no_such_bucket"},
+		{AccessState(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.state.String(); got != tt.expected {
+			t.Errorf("AccessState(%d).String() = %q, want %q", tt.state, got, tt.expected)
+		}
+	}
+}
+
+func TestAccessState_ACL(t *testing.T) {
+	tests := []struct {
+		state    AccessState
+		expected string
+	}{
+		{AccessPublic, "public-read"},
+		{AccessPrivate, "private"},
+		{AccessAllDisabled, "disabled"},
+		{AccessUnknown, "unknown"},
+		{AccessAccountProblem, "account_problem"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.state.ACL(); got != tt.expected {
+			t.Errorf("%s.ACL() = %q, want %q", tt.state, got, tt.expected)
+		}
+	}
+}
+
+func TestAccessState_JSONRoundTrip(t *testing.T) {
+	result := InspectResult{Bucket: "b", State: AccessAccountProblem}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded InspectResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.State != AccessAccountProblem {
+		t.Errorf("State = %v, want %v (json: %s)", decoded.State, AccessAccountProblem, data)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected AccessState
+	}{
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"}, AccessPrivate},
+		{"all access disabled", &smithy.GenericAPIError{Code: "AllAccessDisabled"}, AccessAllDisabled},
+		{"account problem", &smithy.GenericAPIError{Code: "AccountProblem"}, AccessAccountProblem},
+		{"invalid bucket state", &smithy.GenericAPIError{Code: "InvalidBucketState"}, AccessInvalidBucketState},
+		{"acls disabled", &smithy.GenericAPIError{Code: "AccessControlListNotSupported"}, AccessACLsDisabled},
+		{"no such bucket", &smithy.GenericAPIError{Code: "NoSuchBucket"}, AccessNoSuchBucket},
+		{"wrapped", fmt.Errorf("operation error: %w", &smithy.GenericAPIError{Code: "AccountProblem"}), AccessAccountProblem},
+		{"code only in text", errors.New("api error AccessDenied: Access Denied"), AccessUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err).state; got != tt.expected {
+				t.Errorf("classifyError().state = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClassifyError_Redirect(t *testing.T) {
+	header := http.Header{}
+	header.Set("x-amz-bucket-region", "eu-central-1")
+	err := &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 301, Header: header}},
+		Err:      &smithy.GenericAPIError{Code: "PermanentRedirect"},
+	}
+
+	accessErr := classifyError(err)
+
+	if !accessErr.isRedirect() {
+		t.Error("isRedirect() = false, want true for PermanentRedirect")
+	}
+	if accessErr.region != "eu-central-1" {
+		t.Errorf("region = %q, want %q", accessErr.region, "eu-central-1")
+	}
+}
@@ -0,0 +1,275 @@
+package scanner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// archiveBlockSize is the granularity of ranged reads into ZIP files.
+	archiveBlockSize = 64 * 1024
+	// maxArchiveFetch bounds the bytes read from a single archive.
+	maxArchiveFetch = 8 * 1024 * 1024
+)
+
+// ArchiveInfo lists the contents of an archive found in a public bucket.
+type ArchiveInfo struct {
+	Key       string         `json:"key"`
+	Format    string         `json:"format"`
+	Entries   []string       `json:"entries,omitempty"`
+	Sensitive []SensitiveKey `json:"sensitive,omitempty"`
+	Truncated bool           `json:"truncated,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// archiveFormat returns "zip" or "tar.gz" for supported archive keys.
+func archiveFormat(key string) string {
+	lower := strings.ToLower(key)
+	switch {
+	case strings.HasSuffix(lower, ".zip"), strings.HasSuffix(lower, ".jar"), strings.HasSuffix(lower, ".war"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	default:
+		return ""
+	}
+}
+
+// listArchives lists entries of archive keys without downloading them fully.
+func (i *Inspector) listArchives(ctx context.Context, bucket, region string, keys []string) []ArchiveInfo {
+	var archives []ArchiveInfo
+
+	for _, key := range keys {
+		if len(archives) >= i.maxArchives || ctx.Err() != nil {
+			break
+		}
+
+		format := archiveFormat(key)
+		if format == "" {
+			continue
+		}
+
+		info := ArchiveInfo{Key: key, Format: format}
+		var err error
+		switch format {
+		case "zip":
+			info.Entries, info.Truncated, err = i.peekZip(ctx, i.objectURL(bucket, region, key))
+		case "tar.gz":
+			info.Entries, info.Truncated, err = i.peekTarGz(ctx, i.objectURL(bucket, region, key))
+		}
+		if err != nil {
+			info.Error = err.Error()
+		}
+		info.Sensitive = ClassifySensitiveKeys(info.Entries)
+
+		archives = append(archives, info)
+	}
+
+	return archives
+}
+
+// peekZip reads the ZIP central directory through HTTP range requests.
+func (i *Inspector) peekZip(ctx context.Context, url string) ([]string, bool, error) {
+	size, err := i.objectSize(ctx, url)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ra := &rangeReaderAt{ctx: ctx, inspector: i, url: url, size: size, blocks: make(map[int64][]byte)}
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, false, fmt.Errorf("read central directory: %w", err)
+	}
+
+	var entries []string
+	for _, f := range zr.File {
+		if len(entries) >= i.maxArchiveEntries {
+			return entries, true, nil
+		}
+		entries = append(entries, f.Name)
+	}
+
+	return entries, false, nil
+}
+
+// peekTarGz fetches the head of a tar.gz with a range request and collects
+// the first entry names.
+func (i *Inspector) peekTarGz(ctx context.Context, url string) ([]string, bool, error) {
+	if err := i.limiter.Wait(ctx); err != nil {
+		return nil, false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", maxArchiveFetch-1))
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		i.recordTransportError(ctx)
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	i.limiter.RecordResponse(resp.StatusCode)
+
+	// 200 when the server ignores the range; the read below is capped anyway
+	if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body := io.LimitReader(resp.Body, maxArchiveFetch)
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, false, err
+	}
+	defer gz.Close()
+
+	var entries []string
+	tr := tar.NewReader(gz)
+	for {
+		if len(entries) >= i.maxArchiveEntries {
+			return entries, true, nil
+		}
+
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, false, nil
+		}
+		if err != nil {
+			// Hitting the fetch cap mid-archive still yields useful names
+			if len(entries) > 0 && errors.Is(err, io.ErrUnexpectedEOF) {
+				return entries, true, nil
+			}
+			return entries, len(entries) > 0, err
+		}
+		entries = append(entries, hdr.Name)
+	}
+}
+
+// objectSize returns the Content-Length of an object via HEAD.
+func (i *Inspector) objectSize(ctx context.Context, url string) (int64, error) {
+	if err := i.limiter.Wait(ctx); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		i.recordTransportError(ctx)
+		return 0, err
+	}
+	resp.Body.Close()
+	i.limiter.RecordResponse(resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if resp.ContentLength <= 0 {
+		return 0, fmt.Errorf("unknown object size")
+	}
+
+	return resp.ContentLength, nil
+}
+
+// recordTransportError counts a failed request against the inspection rate,
+// unless the peek was cancelled, which says nothing about the server.
+func (i *Inspector) recordTransportError(ctx context.Context) {
+	if ctx.Err() == nil {
+		i.limiter.RecordResponse(0)
+	}
+}
+
+// rangeReaderAt implements io.ReaderAt over HTTP range requests, fetching
+// and caching fixed-size blocks so archive/zip's small reads stay cheap.
+type rangeReaderAt struct {
+	ctx       context.Context
+	inspector *Inspector
+	url       string
+	size      int64
+	blocks    map[int64][]byte
+	fetched   int64
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *rangeReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for n < len(p) && off+int64(n) < r.size {
+		pos := off + int64(n)
+		block, err := r.block(pos / archiveBlockSize)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], block[pos%archiveBlockSize:])
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// block returns the cached block at index idx, fetching it if needed.
+func (r *rangeReaderAt) block(idx int64) ([]byte, error) {
+	if b, ok := r.blocks[idx]; ok {
+		return b, nil
+	}
+
+	start := idx * archiveBlockSize
+	end := start + archiveBlockSize - 1
+	if end >= r.size {
+		end = r.size - 1
+	}
+	if r.fetched+(end-start+1) > maxArchiveFetch {
+		return nil, fmt.Errorf("archive directory exceeds %d byte fetch limit", maxArchiveFetch)
+	}
+
+	if err := r.inspector.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	resp, err := r.inspector.client.Do(req)
+	if err != nil {
+		r.inspector.recordTransportError(r.ctx)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	r.inspector.limiter.RecordResponse(resp.StatusCode)
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return nil, fmt.Errorf("range request not honored: status %d", resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, end-start+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) != end-start+1 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	r.fetched += int64(len(b))
+	r.blocks[idx] = b
+	return b, nil
+}
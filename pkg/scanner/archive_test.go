@@ -0,0 +1,228 @@
+package scanner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func buildZip(t *testing.T, names []string, payload int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(bytes.Repeat([]byte("x"), payload))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildTarGz(t *testing.T, names []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, name := range names {
+		body := []byte("content of " + name)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(body)
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+// archiveServer serves objects with range support and counts bytes sent.
+func archiveServer(t *testing.T, objects map[string][]byte, sent *atomic.Int64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/public-bucket/")
+		data, ok := objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		cw := &countingWriter{ResponseWriter: w, sent: sent}
+		http.ServeContent(cw, r, key, time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	sent *atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.sent.Add(int64(len(p)))
+	return c.ResponseWriter.Write(p)
+}
+
+func newArchiveInspector(server *httptest.Server, cfg *InspectorConfig) *Inspector {
+	cfg.MaxRPS = 1000
+	cfg.PeekArchives = true
+	inspector := NewInspectorWithConfig(cfg)
+	inspector.objectURL = func(bucket, region, key string) string {
+		return fmt.Sprintf("%s/%s/%s", server.URL, bucket, escapeKey(key))
+	}
+	return inspector
+}
+
+func TestArchiveFormat(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"backup.zip", "zip"},
+		{"BACKUP.ZIP", "zip"},
+		{"app.jar", "zip"},
+		{"site.tar.gz", "tar.gz"},
+		{"site.tgz", "tar.gz"},
+		{"dump.sql", ""},
+		{"photo.gz", ""},
+	}
+
+	for _, tt := range tests {
+		if got := archiveFormat(tt.key); got != tt.expected {
+			t.Errorf("archiveFormat(%q) = %q, want %q", tt.key, got, tt.expected)
+		}
+	}
+}
+
+func TestInspector_listArchives_Zip(t *testing.T) {
+	// Large payload ensures only the tail of the archive is fetched
+	zipData := buildZip(t, []string{"www/index.html", "config/.env", "db/users.sql"}, 200*1024)
+	var sent atomic.Int64
+	server := archiveServer(t, map[string][]byte{"site-backup.zip": zipData}, &sent)
+	inspector := newArchiveInspector(server, &InspectorConfig{})
+
+	archives := inspector.listArchives(context.Background(), "public-bucket", "us-east-1", []string{"readme.txt", "site-backup.zip"})
+
+	if len(archives) != 1 {
+		t.Fatalf("listArchives() returned %d archives, want 1", len(archives))
+	}
+	a := archives[0]
+	if a.Error != "" {
+		t.Fatalf("archive error = %q", a.Error)
+	}
+	if len(a.Entries) != 3 || a.Entries[0] != "www/index.html" {
+		t.Errorf("Entries = %v, want 3 names starting with www/index.html", a.Entries)
+	}
+	if len(a.Sensitive) != 2 {
+		t.Errorf("Sensitive = %+v, want .env and users.sql", a.Sensitive)
+	}
+	if sent.Load() >= int64(len(zipData))/2 {
+		t.Errorf("fetched %d of %d bytes, want only the central directory", sent.Load(), len(zipData))
+	}
+}
+
+func TestInspector_listArchives_TarGz(t *testing.T) {
+	names := []string{"app/", "app/main.go", "app/id_rsa", "app/readme.md"}
+	var sent atomic.Int64
+	server := archiveServer(t, map[string][]byte{"src.tar.gz": buildTarGz(t, names)}, &sent)
+	inspector := newArchiveInspector(server, &InspectorConfig{MaxArchiveEntries: 3})
+
+	archives := inspector.listArchives(context.Background(), "public-bucket", "us-east-1", []string{"src.tar.gz"})
+
+	if len(archives) != 1 {
+		t.Fatalf("listArchives() returned %d archives, want 1", len(archives))
+	}
+	a := archives[0]
+	if len(a.Entries) != 3 || !a.Truncated {
+		t.Errorf("Entries = %v, Truncated = %v; want 3 entries, truncated", a.Entries, a.Truncated)
+	}
+	if len(a.Sensitive) != 1 || a.Sensitive[0].Category != "private_key" {
+		t.Errorf("Sensitive = %+v, want id_rsa as private_key", a.Sensitive)
+	}
+}
+
+func TestInspector_listArchives_MaxArchives(t *testing.T) {
+	zipData := buildZip(t, []string{"a.txt"}, 1)
+	objects := map[string][]byte{"1.zip": zipData, "2.zip": zipData, "3.zip": zipData}
+	var sent atomic.Int64
+	server := archiveServer(t, objects, &sent)
+	inspector := newArchiveInspector(server, &InspectorConfig{MaxArchives: 2})
+
+	archives := inspector.listArchives(context.Background(), "public-bucket", "us-east-1", []string{"1.zip", "2.zip", "3.zip"})
+
+	if len(archives) != 2 {
+		t.Errorf("listArchives() returned %d archives, want 2", len(archives))
+	}
+}
+
+func TestInspector_listArchives_Missing(t *testing.T) {
+	var sent atomic.Int64
+	server := archiveServer(t, map[string][]byte{}, &sent)
+	inspector := newArchiveInspector(server, &InspectorConfig{})
+
+	archives := inspector.listArchives(context.Background(), "public-bucket", "us-east-1", []string{"gone.zip"})
+
+	if len(archives) != 1 || archives[0].Error == "" {
+		t.Errorf("listArchives() = %+v, want one archive with an error", archives)
+	}
+}
+
+func TestInspector_peekTarGz_Range(t *testing.T) {
+	data := buildTarGz(t, []string{"app/main.go"})
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "src.tar.gz", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+	inspector := newArchiveInspector(server, &InspectorConfig{})
+
+	entries, _, err := inspector.peekTarGz(context.Background(), server.URL+"/public-bucket/src.tar.gz")
+	if err != nil {
+		t.Fatalf("peekTarGz() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0] != "app/main.go" {
+		t.Errorf("entries = %v, want [app/main.go]", entries)
+	}
+	if want := fmt.Sprintf("bytes=0-%d", maxArchiveFetch-1); len(ranges) != 1 || ranges[0] != want {
+		t.Errorf("Range headers = %v, want [%s]", ranges, want)
+	}
+}
+
+func TestInspector_peekTarGz_Canceled(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	inspector := newArchiveInspector(server, &InspectorConfig{})
+
+	// Five network errors in a row would lower the rate
+	for range 5 {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+		if _, _, err := inspector.peekTarGz(ctx, server.URL+"/public-bucket/src.tar.gz"); err == nil {
+			t.Fatal("peekTarGz() expected error after cancellation")
+		}
+	}
+	if inspector.CurrentRPS() != 1000 {
+		t.Errorf("CurrentRPS() = %v, want 1000 (cancellation must not throttle)", inspector.CurrentRPS())
+	}
+}
@@ -2,40 +2,133 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/xeloxa/s3finder/pkg/ratelimit"
 )
 
+// maxSampleKeys is how many listed keys are reported in SampleKeys, as
+// listing always has. The archive peek sees the whole first page.
+const maxSampleKeys = 10
+
+// maxThrottleRetries is how many times an SDK call is retried after S3 asks
+// us to slow down. Each retry waits on the inspection limiter again.
+const maxThrottleRetries = 2
+
 // InspectResult contains detailed information about a discovered bucket.
 type InspectResult struct {
-	Bucket      string    `json:"bucket"`
-	Exists      bool      `json:"exists"`
-	IsPublic    bool      `json:"is_public"`
-	ACL         string    `json:"acl"`
-	Region      string    `json:"region"`
-	ObjectCount int       `json:"object_count"`
-	SampleKeys  []string  `json:"sample_keys,omitempty"`
-	Error       string    `json:"error,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
+	Bucket          string        `json:"bucket"`
+	Exists          bool          `json:"exists"`
+	IsPublic        bool          `json:"is_public"`
+	ACL             string        `json:"acl"`
+	State           AccessState   `json:"state"`
+	Region          string        `json:"region"`
+	ObjectCount     int           `json:"object_count"`
+	SampleKeys      []string      `json:"sample_keys,omitempty"`
+	ReadableObjects []ObjectInfo  `json:"readable_objects,omitempty"` // Known keys readable despite denied listing
+	Archives        []ArchiveInfo `json:"archives,omitempty"`
+	Error           string        `json:"error,omitempty"`
+	Timestamp       time.Time     `json:"timestamp"`
 }
 
 // Inspector performs deep inspection on discovered buckets using AWS SDK.
 type Inspector struct {
-	timeout time.Duration
+	timeout    time.Duration
+	limiter    *ratelimit.AdaptiveLimiter
+	client     *http.Client
+	objectKeys []string
+	objectURL  func(bucket, region, key string) string
+
+	peekArchives      bool
+	maxArchives       int
+	maxArchiveEntries int
 }
 
-// NewInspector creates a new Inspector.
+// InspectorConfig holds configuration for the Inspector.
+type InspectorConfig struct {
+	Timeout time.Duration
+	MaxRPS  float64 // Ceiling for inspection requests, independent of the prober
+	// ObjectKeys are HEAD-probed in buckets that deny listing. Empty disables probing.
+	ObjectKeys []string
+	// PeekArchives lists entries of ZIP and tar.gz objects in public buckets.
+	PeekArchives      bool
+	MaxArchives       int // Archives peeked per bucket
+	MaxArchiveEntries int // Entry names kept per archive
+}
+
+// DefaultInspectorConfig returns conservative defaults for deep inspection.
+func DefaultInspectorConfig() *InspectorConfig {
+	return &InspectorConfig{
+		Timeout:           30 * time.Second,
+		MaxRPS:            20,
+		MaxArchives:       5,
+		MaxArchiveEntries: 200,
+	}
+}
+
+// inspectFloor is the lowest rate the inspection pool backs off to. The
+// inspection ceiling is usually far below the probe rate, so the limiter's
+// usual 20 RPS floor would leave no room to back off; a quarter of the
+// ceiling is used instead.
+func inspectFloor(maxRPS float64) float64 {
+	return min(20, maxRPS/4)
+}
+
+// NewInspector creates a new Inspector with the default inspection rate.
 func NewInspector(timeout time.Duration) *Inspector {
+	return NewInspectorWithConfig(&InspectorConfig{Timeout: timeout})
+}
+
+// NewInspectorWithConfig creates a new Inspector with the given configuration.
+func NewInspectorWithConfig(cfg *InspectorConfig) *Inspector {
+	defaults := DefaultInspectorConfig()
+	if cfg == nil {
+		cfg = defaults
+	}
+
+	timeout := cfg.Timeout
 	if timeout <= 0 {
-		timeout = 30 * time.Second
+		timeout = defaults.Timeout
+	}
+
+	maxRPS := cfg.MaxRPS
+	if maxRPS <= 0 {
+		maxRPS = defaults.MaxRPS
+	}
+
+	maxArchives := cfg.MaxArchives
+	if maxArchives <= 0 {
+		maxArchives = defaults.MaxArchives
+	}
+
+	maxArchiveEntries := cfg.MaxArchiveEntries
+	if maxArchiveEntries <= 0 {
+		maxArchiveEntries = defaults.MaxArchiveEntries
+	}
+
+	return &Inspector{
+		timeout:           timeout,
+		limiter:           ratelimit.NewWithFloor(maxRPS, inspectFloor(maxRPS)),
+		client:            &http.Client{Timeout: 10 * time.Second},
+		objectKeys:        cfg.ObjectKeys,
+		objectURL:         anonymousObjectURL,
+		peekArchives:      cfg.PeekArchives,
+		maxArchives:       maxArchives,
+		maxArchiveEntries: maxArchiveEntries,
 	}
-	return &Inspector{timeout: timeout}
+}
+
+// CurrentRPS returns the current inspection rate limit.
+func (i *Inspector) CurrentRPS() float64 {
+	return i.limiter.CurrentRPS()
 }
 
 // Inspect performs deep analysis on a bucket.
@@ -47,11 +140,11 @@ func (i *Inspector) Inspect(ctx context.Context, bucket string) *InspectResult {
 		Timestamp:   time.Now(),
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, i.timeout)
+	listCtx, cancel := context.WithTimeout(ctx, i.timeout)
 	defer cancel()
 
 	// Get bucket region first
-	region, err := i.getBucketRegion(ctx, bucket)
+	region, err := i.getBucketRegion(listCtx, bucket)
 	if err != nil {
 		result.Error = fmt.Sprintf("region lookup failed: %v", err)
 		result.Region = "unknown"
@@ -60,11 +153,29 @@ func (i *Inspector) Inspect(ctx context.Context, bucket string) *InspectResult {
 	}
 
 	// Check ACL and attempt object listing
-	isPublic, acl, objects, count := i.checkPublicAccess(ctx, bucket, region)
-	result.IsPublic = isPublic
-	result.ACL = acl
+	state, listed, count := i.checkPublicAccess(listCtx, bucket, region)
+	result.State = state
+	result.IsPublic = state == AccessPublic
+	result.ACL = state.ACL()
 	result.ObjectCount = count
-	result.SampleKeys = objects
+	result.SampleKeys = listed
+	if len(listed) > maxSampleKeys {
+		result.SampleKeys = listed[:maxSampleKeys]
+	}
+
+	// Look inside exposed backups
+	if state == AccessPublic && i.peekArchives {
+		peekCtx, cancel := context.WithTimeout(ctx, i.timeout)
+		defer cancel()
+		result.Archives = i.listArchives(peekCtx, bucket, result.Region, listed)
+	}
+
+	// Listing denied: individual objects may still be public
+	if state == AccessPrivate && len(i.objectKeys) > 0 {
+		probeCtx, cancel := context.WithTimeout(ctx, i.timeout)
+		defer cancel()
+		result.ReadableObjects = i.probeKeys(probeCtx, bucket, result.Region)
+	}
 
 	return result
 }
@@ -72,20 +183,28 @@ func (i *Inspector) Inspect(ctx context.Context, bucket string) *InspectResult {
 // getBucketRegion determines which AWS region hosts the bucket.
 func (i *Inspector) getBucketRegion(ctx context.Context, bucket string) (string, error) {
 	// Use HTTP HEAD request to get region from x-amz-bucket-region header
-	// This is more reliable than GetBucketLocation which requires permissions
-	url := fmt.Sprintf("https://%s.s3.amazonaws.com", bucket)
+	// This is more reliable than GetBucketLocation which requires permissions.
+	// The global endpoint answers for every region, path-style for dotted names.
+	url := anonymousObjectURL(bucket, "", "")
 
 	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
 	if err != nil {
 		return "us-east-1", nil
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	if err := i.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	resp, err := i.client.Do(req)
 	if err != nil {
+		if ctx.Err() == nil {
+			i.limiter.RecordResponse(0)
+		}
 		return "us-east-1", nil
 	}
 	defer resp.Body.Close()
+	i.limiter.RecordResponse(resp.StatusCode)
 
 	// x-amz-bucket-region header is returned regardless of access permissions
 	if region := resp.Header.Get("x-amz-bucket-region"); region != "" {
@@ -96,53 +215,53 @@ func (i *Inspector) getBucketRegion(ctx context.Context, bucket string) (string,
 }
 
 // checkPublicAccess attempts anonymous listing to determine if bucket is public.
-func (i *Inspector) checkPublicAccess(ctx context.Context, bucket, region string) (bool, string, []string, int) {
+func (i *Inspector) checkPublicAccess(ctx context.Context, bucket, region string) (AccessState, []string, int) {
 	return i.checkPublicAccessWithRetry(ctx, bucket, region, false)
 }
 
 // checkPublicAccessWithRetry attempts anonymous listing with region retry support.
-func (i *Inspector) checkPublicAccessWithRetry(ctx context.Context, bucket, region string, retried bool) (bool, string, []string, int) {
+func (i *Inspector) checkPublicAccessWithRetry(ctx context.Context, bucket, region string, retried bool) (AccessState, []string, int) {
 	if region == "" || region == "unknown" {
 		region = "us-east-1"
 	}
 
-	// Create anonymous client
+	// Create anonymous client. SDK retries are disabled so that every attempt
+	// goes through the inspection limiter.
 	cfg, err := config.LoadDefaultConfig(ctx,
 		config.WithRegion(region),
 		config.WithCredentialsProvider(aws.AnonymousCredentials{}),
+		config.WithRetryMaxAttempts(1),
 	)
 	if err != nil {
-		return false, "unknown", nil, -1
+		return AccessUnknown, nil, -1
 	}
 
 	client := s3.NewFromConfig(cfg)
 
 	// Try to list objects anonymously
-	output, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket:  aws.String(bucket),
-		MaxKeys: aws.Int32(100),
-	})
-	if err != nil {
-		errStr := err.Error()
-
-		// Check for region mismatch - retry with correct region
-		if !retried && (strings.Contains(errStr, "BucketRegionError") ||
-			strings.Contains(errStr, "PermanentRedirect") ||
-			strings.Contains(errStr, "please use the correct region")) {
-			correctRegion := i.parseRegionFromError(errStr)
-			if correctRegion != "" && correctRegion != region {
-				return i.checkPublicAccessWithRetry(ctx, bucket, correctRegion, true)
-			}
+	var output *s3.ListObjectsV2Output
+	for attempt := 0; ; attempt++ {
+		if err = i.limiter.Wait(ctx); err != nil {
+			return AccessUnknown, nil, -1
 		}
-
-		// Check if it's an access denied error
-		if strings.Contains(errStr, "AccessDenied") {
-			return false, "private", nil, -1
+		output, err = client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:  aws.String(bucket),
+			MaxKeys: aws.Int32(100),
+		})
+		i.recordSDKResult(ctx, err)
+		if err == nil || !isThrottleError(err) || attempt >= maxThrottleRetries {
+			break
 		}
-		if strings.Contains(errStr, "AllAccessDisabled") {
-			return false, "disabled", nil, -1
+	}
+	if err != nil {
+		accessErr := classifyError(err)
+
+		// Check for region mismatch - retry in the region from x-amz-bucket-region
+		if !retried && accessErr.isRedirect() && accessErr.region != "" && accessErr.region != region {
+			return i.checkPublicAccessWithRetry(ctx, bucket, accessErr.region, true)
 		}
-		return false, "unknown", nil, -1
+
+		return accessErr.state, nil, -1
 	}
 
 	// Successfully listed objects - bucket is public!
@@ -150,9 +269,6 @@ func (i *Inspector) checkPublicAccessWithRetry(ctx context.Context, bucket, regi
 	for _, obj := range output.Contents {
 		if obj.Key != nil {
 			keys = append(keys, *obj.Key)
-			if len(keys) >= 10 {
-				break
-			}
 		}
 	}
 
@@ -161,23 +277,43 @@ func (i *Inspector) checkPublicAccessWithRetry(ctx context.Context, bucket, regi
 		count = -2 // Indicates more than returned
 	}
 
-	return true, "public-read", keys, count
+	return AccessPublic, keys, count
 }
 
-// parseRegionFromError extracts region from AWS error messages.
-func (i *Inspector) parseRegionFromError(errMsg string) string {
-	regions := []string{
-		"us-east-1", "us-east-2", "us-west-1", "us-west-2",
-		"eu-west-1", "eu-west-2", "eu-west-3", "eu-central-1",
-		"ap-south-1", "ap-northeast-1", "ap-northeast-2", "ap-southeast-1", "ap-southeast-2",
-		"sa-east-1", "ca-central-1",
+// recordSDKResult feeds the outcome of an SDK call back into the inspection limiter.
+func (i *Inspector) recordSDKResult(ctx context.Context, err error) {
+	switch {
+	case err == nil:
+		i.limiter.RecordResponse(http.StatusOK)
+	case ctx.Err() != nil:
+		// Cancellation says nothing about the server's health
+	case isThrottleError(err):
+		i.limiter.RecordResponse(http.StatusServiceUnavailable)
+	default:
+		var respErr *smithyhttp.ResponseError
+		if errors.As(err, &respErr) {
+			i.limiter.RecordResponse(respErr.HTTPStatusCode())
+		} else {
+			i.limiter.RecordResponse(0)
+		}
 	}
+}
 
-	for _, r := range regions {
-		if strings.Contains(errMsg, r) {
-			return r
+// isThrottleError reports whether an SDK error asks the client to back off.
+func isThrottleError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "SlowDown", "Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequests":
+			return true
 		}
 	}
 
-	return "us-east-1"
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		code := respErr.HTTPStatusCode()
+		return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+	}
+
+	return false
 }
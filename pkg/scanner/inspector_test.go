@@ -1,8 +1,14 @@
 package scanner
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"testing"
 	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 func TestNewInspector(t *testing.T) {
@@ -30,32 +36,90 @@ func TestNewInspector(t *testing.T) {
 	}
 }
 
-func TestInspector_parseRegionFromError(t *testing.T) {
-	inspector := NewInspector(30 * time.Second)
+func TestNewInspectorWithConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             *InspectorConfig
+		expectedTimeout time.Duration
+		expectedRPS     float64
+	}{
+		{"nil config uses defaults", nil, 30 * time.Second, 20},
+		{"custom values", &InspectorConfig{Timeout: 5 * time.Second, MaxRPS: 3}, 5 * time.Second, 3},
+		{"zero values default", &InspectorConfig{}, 30 * time.Second, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := NewInspectorWithConfig(tt.cfg)
+
+			if inspector.timeout != tt.expectedTimeout {
+				t.Errorf("timeout = %v, want %v", inspector.timeout, tt.expectedTimeout)
+			}
+			if inspector.CurrentRPS() != tt.expectedRPS {
+				t.Errorf("CurrentRPS() = %v, want %v", inspector.CurrentRPS(), tt.expectedRPS)
+			}
+		})
+	}
+}
+
+func newResponseError(status int) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      errors.New("response error"),
+	}
+}
 
+func TestIsThrottleError(t *testing.T) {
 	tests := []struct {
 		name     string
-		errMsg   string
-		expected string
+		err      error
+		expected bool
 	}{
-		{"us-east-1", "PermanentRedirect to us-east-1", "us-east-1"},
-		{"us-west-2", "bucket is in us-west-2 region", "us-west-2"},
-		{"eu-west-1", "redirect to eu-west-1.amazonaws.com", "eu-west-1"},
-		{"ap-northeast-1", "error in ap-northeast-1", "ap-northeast-1"},
-		{"no region found", "some random error message", "us-east-1"},
-		{"empty string", "", "us-east-1"},
+		{"SlowDown", &smithy.GenericAPIError{Code: "SlowDown"}, true},
+		{"Throttling", &smithy.GenericAPIError{Code: "Throttling"}, true},
+		{"AccessDenied", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"HTTP 503", newResponseError(503), true},
+		{"HTTP 429", newResponseError(429), true},
+		{"HTTP 403", newResponseError(403), false},
+		{"plain error", errors.New("boom"), false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := inspector.parseRegionFromError(tt.errMsg)
-			if result != tt.expected {
-				t.Errorf("parseRegionFromError(%q) = %q, want %q", tt.errMsg, result, tt.expected)
+			if got := isThrottleError(tt.err); got != tt.expected {
+				t.Errorf("isThrottleError() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
 }
 
+func TestInspector_recordSDKResult_SlowDown(t *testing.T) {
+	inspector := NewInspectorWithConfig(&InspectorConfig{MaxRPS: 100})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		inspector.recordSDKResult(ctx, &smithy.GenericAPIError{Code: "SlowDown"})
+	}
+
+	if inspector.CurrentRPS() != 50 {
+		t.Errorf("CurrentRPS() after 3x SlowDown = %v, want %v", inspector.CurrentRPS(), 50.0)
+	}
+}
+
+func TestInspector_recordSDKResult_IgnoresCanceled(t *testing.T) {
+	inspector := NewInspectorWithConfig(&InspectorConfig{MaxRPS: 100})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 10; i++ {
+		inspector.recordSDKResult(ctx, ctx.Err())
+	}
+
+	if inspector.CurrentRPS() != 100 {
+		t.Errorf("CurrentRPS() = %v, want %v (cancellation must not throttle)", inspector.CurrentRPS(), 100.0)
+	}
+}
+
 func TestInspectResult_Fields(t *testing.T) {
 	now := time.Now()
 	result := &InspectResult{
@@ -0,0 +1,122 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ObjectInfo describes an object that could be read anonymously.
+type ObjectInfo struct {
+	Key          string `json:"key"`
+	Size         int64  `json:"size"`
+	ContentType  string `json:"content_type,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// commonObjectKeys are object names frequently left readable in buckets
+// that deny listing.
+var commonObjectKeys = []string{
+	"index.html", "index.htm", "robots.txt", "favicon.ico",
+	".git/config", ".git/HEAD", ".env", ".htpasswd", ".DS_Store",
+	"config.json", "config.yml", "config.yaml", "settings.json", "credentials.json",
+	"backup.zip", "backup.tar.gz", "backup.sql", "dump.sql", "db.sql", "database.sql",
+	"users.csv", "export.csv", "data.json", "package.json", "composer.json",
+	"web.config", "wp-config.php", "id_rsa", "terraform.tfstate",
+}
+
+// dumpBases and dumpExts build dated dump names such as backup-2024.sql.gz.
+var (
+	dumpBases = []string{"backup", "dump", "db", "database"}
+	dumpExts  = []string{".sql", ".sql.gz", ".zip", ".tar.gz"}
+)
+
+// DefaultObjectKeys returns the built-in key wordlist, extended with dated
+// dump names for each four-digit year (e.g. "-2024" from the permutation engine).
+func DefaultObjectKeys(years []string) []string {
+	keys := append([]string(nil), commonObjectKeys...)
+
+	for _, year := range years {
+		year = strings.Trim(year, "-.")
+		if len(year) != 4 {
+			continue
+		}
+		for _, base := range dumpBases {
+			for _, ext := range dumpExts {
+				keys = append(keys, base+"-"+year+ext)
+			}
+		}
+	}
+
+	return keys
+}
+
+// anonymousObjectURL builds the anonymous URL for an object. Dotted bucket
+// names use path-style URLs, like the SDK's UsePathStyle, because the
+// *.s3.amazonaws.com certificate does not cover them. Path-style requests
+// must go to the bucket's regional endpoint, or S3 answers with a redirect;
+// the global endpoint is only used when the region is unknown.
+func anonymousObjectURL(bucket, region, key string) string {
+	if strings.Contains(bucket, ".") {
+		if region == "" || region == "unknown" {
+			return fmt.Sprintf("https://s3.amazonaws.com/%s/%s", bucket, escapeKey(key))
+		}
+		return fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", region, bucket, escapeKey(key))
+	}
+	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucket, escapeKey(key))
+}
+
+// escapeKey escapes each path segment of an object key.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// probeKeys HEADs each known key and returns the objects that are readable.
+func (i *Inspector) probeKeys(ctx context.Context, bucket, region string) []ObjectInfo {
+	var found []ObjectInfo
+
+	for _, key := range i.objectKeys {
+		if err := i.limiter.Wait(ctx); err != nil {
+			break
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, i.objectURL(bucket, region, key), nil)
+		if err != nil {
+			continue
+		}
+
+		resp, err := i.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			i.limiter.RecordResponse(0)
+			continue
+		}
+		resp.Body.Close()
+		i.limiter.RecordResponse(resp.StatusCode)
+
+		if resp.StatusCode != http.StatusOK {
+			continue
+		}
+
+		info := ObjectInfo{
+			Key:         key,
+			Size:        resp.ContentLength,
+			ContentType: resp.Header.Get("Content-Type"),
+		}
+		if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+			info.LastModified = lm.UTC().Format(time.RFC3339)
+		}
+		found = append(found, info)
+	}
+
+	return found
+}
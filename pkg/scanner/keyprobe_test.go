@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultObjectKeys(t *testing.T) {
+	keys := DefaultObjectKeys([]string{"", "-2024", "-24", "2025"})
+
+	keySet := make(map[string]bool)
+	for _, k := range keys {
+		keySet[k] = true
+	}
+
+	for _, expected := range []string{"index.html", ".git/config", "config.json", "backup.zip", "backup-2024.sql", "dump-2025.sql.gz"} {
+		if !keySet[expected] {
+			t.Errorf("DefaultObjectKeys() missing %q", expected)
+		}
+	}
+	if keySet["backup-24.sql"] {
+		t.Error("DefaultObjectKeys() should only build dated names from four-digit years")
+	}
+}
+
+func TestEscapeKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{".git/config", ".git/config"},
+		{"my file.txt", "my%20file.txt"},
+		{"a/b c/d", "a/b%20c/d"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeKey(tt.key); got != tt.expected {
+			t.Errorf("escapeKey(%q) = %q, want %q", tt.key, got, tt.expected)
+		}
+	}
+}
+
+func TestAnonymousObjectURL(t *testing.T) {
+	tests := []struct {
+		bucket   string
+		region   string
+		key      string
+		expected string
+	}{
+		{"acme-backups", "us-east-1", "db/dump.sql", "https://acme-backups.s3.amazonaws.com/db/dump.sql"},
+		{"static.acme.com", "us-east-1", "backup.zip", "https://s3.us-east-1.amazonaws.com/static.acme.com/backup.zip"},
+		{"static.acme.com", "eu-west-1", "a b/.env", "https://s3.eu-west-1.amazonaws.com/static.acme.com/a%20b/.env"},
+		{"static.acme.com", "unknown", "backup.zip", "https://s3.amazonaws.com/static.acme.com/backup.zip"},
+		{"static.acme.com", "", "backup.zip", "https://s3.amazonaws.com/static.acme.com/backup.zip"},
+	}
+
+	for _, tt := range tests {
+		if got := anonymousObjectURL(tt.bucket, tt.region, tt.key); got != tt.expected {
+			t.Errorf("anonymousObjectURL(%q, %q, %q) = %q, want %q", tt.bucket, tt.region, tt.key, got, tt.expected)
+		}
+	}
+}
+
+func TestInspector_probeKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		switch r.URL.Path {
+		case "/private-bucket/index.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Length", "42")
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			w.WriteHeader(http.StatusOK)
+		case "/private-bucket/.git/config":
+			w.Header().Set("Content-Length", "7")
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	inspector := NewInspectorWithConfig(&InspectorConfig{
+		MaxRPS:     1000,
+		ObjectKeys: []string{"index.html", "backup.zip", ".git/config"},
+	})
+	inspector.objectURL = func(bucket, region, key string) string {
+		if region != "eu-west-1" {
+			t.Errorf("objectURL() region = %q, want eu-west-1", region)
+		}
+		return server.URL + "/" + bucket + "/" + escapeKey(key)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	found := inspector.probeKeys(ctx, "private-bucket", "eu-west-1")
+
+	if len(found) != 2 {
+		t.Fatalf("probeKeys() found %d objects, want 2: %+v", len(found), found)
+	}
+	if found[0].Key != "index.html" || found[0].Size != 42 || found[0].ContentType != "text/html" {
+		t.Errorf("found[0] = %+v, want index.html with size 42 and text/html", found[0])
+	}
+	if !strings.HasPrefix(found[0].LastModified, "2024-01-01") {
+		t.Errorf("LastModified = %q, want 2024-01-01...", found[0].LastModified)
+	}
+	if found[1].Key != ".git/config" {
+		t.Errorf("found[1].Key = %q, want .git/config", found[1].Key)
+	}
+}
+
+func TestInspector_probeKeys_ContextCanceled(t *testing.T) {
+	inspector := NewInspectorWithConfig(&InspectorConfig{ObjectKeys: []string{"index.html"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if found := inspector.probeKeys(ctx, "private-bucket", "eu-west-1"); len(found) != 0 {
+		t.Errorf("probeKeys() with canceled context found %d objects, want 0", len(found))
+	}
+}
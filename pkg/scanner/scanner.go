@@ -13,6 +13,7 @@ type ScanResult struct {
 	Bucket    string         `json:"bucket"`
 	Probe     ProbeResult    `json:"probe_result"`
 	Inspect   *InspectResult `json:"inspect,omitempty"`
+	Origin    string         `json:"origin,omitempty"` // Where the name was found, e.g. a CNAME host
 	Warning   string         `json:"warning,omitempty"`
 	Error     string         `json:"error,omitempty"`
 	Timestamp time.Time      `json:"timestamp"`
@@ -20,14 +21,15 @@ type ScanResult struct {
 
 // Stats tracks scanning statistics.
 type Stats struct {
-	Total     int64
-	Scanned   int64
-	Found     int64
-	Public    int64
-	Private   int64
-	Errors    int64
-	NotFound  int64
-	StartTime time.Time
+	Total        int64
+	Scanned      int64
+	Found        int64
+	Public       int64
+	Private      int64
+	Errors       int64
+	NotFound     int64
+	InspectQueue int64 // Found buckets waiting for deep inspection
+	StartTime    time.Time
 }
 
 // Scanner orchestrates the bucket enumeration process.
@@ -35,6 +37,7 @@ type Scanner struct {
 	prober         *Prober
 	inspector      *Inspector
 	workers        int
+	inspectWorkers int
 	deepInspect    bool
 	stats          Stats
 	resultsChan    chan *ScanResult
@@ -45,19 +48,25 @@ type Scanner struct {
 
 // Config holds scanner configuration.
 type Config struct {
-	Workers     int
-	MaxRPS      float64
-	Timeout     time.Duration
-	DeepInspect bool
+	Workers        int
+	MaxRPS         float64
+	Timeout        time.Duration
+	DeepInspect    bool
+	InspectWorkers int      // Size of the deep inspection pool
+	InspectRPS     float64  // Rate ceiling for inspection SDK calls
+	ObjectKeys     []string // Keys probed in buckets that deny listing
+	PeekArchives   bool     // List entries of archives found in public buckets
 }
 
 // DefaultConfig returns sensible default configuration.
 func DefaultConfig() *Config {
 	return &Config{
-		Workers:     10,
-		MaxRPS:      50,
-		Timeout:     10 * time.Second,
-		DeepInspect: true,
+		Workers:        10,
+		MaxRPS:         50,
+		Timeout:        10 * time.Second,
+		DeepInspect:    true,
+		InspectWorkers: 10,
+		InspectRPS:     20,
 	}
 }
 
@@ -75,24 +84,30 @@ func New(cfg *Config) *Scanner {
 		MaxRPS:              cfg.MaxRPS,
 	}
 
+	inspectWorkers := cfg.InspectWorkers
+	if inspectWorkers <= 0 {
+		inspectWorkers = 10
+	}
+
 	return &Scanner{
-		prober:      NewProber(proberCfg),
-		inspector:   NewInspector(30 * time.Second),
-		workers:     cfg.Workers,
-		deepInspect: cfg.DeepInspect,
-		resultsChan: make(chan *ScanResult, 1000),
-		inspectChan: make(chan *ScanResult, 500),
+		prober: NewProber(proberCfg),
+		inspector: NewInspectorWithConfig(&InspectorConfig{
+			Timeout:      30 * time.Second,
+			MaxRPS:       cfg.InspectRPS,
+			ObjectKeys:   cfg.ObjectKeys,
+			PeekArchives: cfg.PeekArchives,
+		}),
+		workers:        cfg.Workers,
+		inspectWorkers: inspectWorkers,
+		deepInspect:    cfg.DeepInspect,
+		resultsChan:    make(chan *ScanResult, 1000),
+		inspectChan:    make(chan *ScanResult, 500),
 	}
 }
 
 // Scan starts scanning the provided bucket names.
 // Returns a channel that receives results as they're found.
 func (s *Scanner) Scan(ctx context.Context, names []string) <-chan *ScanResult {
-	s.stats = Stats{
-		Total:     int64(len(names)),
-		StartTime: time.Now(),
-	}
-
 	namesChan := make(chan string, 1000)
 
 	// Producer: feed names into channel
@@ -107,10 +122,22 @@ func (s *Scanner) Scan(ctx context.Context, names []string) <-chan *ScanResult {
 		}
 	}()
 
+	return s.ScanStream(ctx, namesChan, int64(len(names)))
+}
+
+// ScanStream scans names as they arrive on the channel, for generators too
+// large to materialize. total is the expected count used for stats. The
+// caller closes names when done.
+func (s *Scanner) ScanStream(ctx context.Context, names <-chan string, total int64) <-chan *ScanResult {
+	s.stats = Stats{
+		Total:     total,
+		StartTime: time.Now(),
+	}
+	namesChan := names
+
 	// Start inspection workers (separate pool for non-blocking deep inspection)
 	if s.deepInspect {
-		inspectWorkers := 10 // Dedicated inspection workers
-		for i := 0; i < inspectWorkers; i++ {
+		for i := 0; i < s.inspectWorkers; i++ {
 			s.inspectWg.Add(1)
 			go s.inspectionWorker(ctx)
 		}
@@ -243,14 +270,15 @@ func (s *Scanner) Stats() Stats {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return Stats{
-		Total:     s.stats.Total,
-		Scanned:   atomic.LoadInt64(&s.stats.Scanned),
-		Found:     atomic.LoadInt64(&s.stats.Found),
-		Public:    atomic.LoadInt64(&s.stats.Public),
-		Private:   atomic.LoadInt64(&s.stats.Private),
-		Errors:    atomic.LoadInt64(&s.stats.Errors),
-		NotFound:  atomic.LoadInt64(&s.stats.NotFound),
-		StartTime: s.stats.StartTime,
+		Total:        s.stats.Total,
+		Scanned:      atomic.LoadInt64(&s.stats.Scanned),
+		Found:        atomic.LoadInt64(&s.stats.Found),
+		Public:       atomic.LoadInt64(&s.stats.Public),
+		Private:      atomic.LoadInt64(&s.stats.Private),
+		Errors:       atomic.LoadInt64(&s.stats.Errors),
+		NotFound:     atomic.LoadInt64(&s.stats.NotFound),
+		InspectQueue: int64(len(s.inspectChan)),
+		StartTime:    s.stats.StartTime,
 	}
 }
 
@@ -258,3 +286,8 @@ func (s *Scanner) Stats() Stats {
 func (s *Scanner) CurrentRPS() float64 {
 	return s.prober.CurrentRPS()
 }
+
+// InspectRPS returns the current deep inspection rate limit.
+func (s *Scanner) InspectRPS() float64 {
+	return s.inspector.CurrentRPS()
+}
@@ -21,6 +21,12 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.DeepInspect != true {
 		t.Errorf("DeepInspect = %v, want %v", cfg.DeepInspect, true)
 	}
+	if cfg.InspectWorkers != 10 {
+		t.Errorf("InspectWorkers = %d, want %d", cfg.InspectWorkers, 10)
+	}
+	if cfg.InspectRPS != 20 {
+		t.Errorf("InspectRPS = %v, want %v", cfg.InspectRPS, 20.0)
+	}
 }
 
 func TestNew_NilConfig(t *testing.T) {
@@ -58,6 +64,41 @@ func TestNew_CustomConfig(t *testing.T) {
 	}
 }
 
+func TestNew_InspectPool(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             *Config
+		expectedWorkers int
+		expectedRPS     float64
+	}{
+		{"custom pool", &Config{Workers: 5, InspectWorkers: 3, InspectRPS: 7}, 3, 7},
+		{"zero values default", &Config{Workers: 5}, 10, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(tt.cfg)
+
+			if scanner.inspectWorkers != tt.expectedWorkers {
+				t.Errorf("inspectWorkers = %d, want %d", scanner.inspectWorkers, tt.expectedWorkers)
+			}
+			if scanner.InspectRPS() != tt.expectedRPS {
+				t.Errorf("InspectRPS() = %v, want %v", scanner.InspectRPS(), tt.expectedRPS)
+			}
+		})
+	}
+}
+
+func TestScanner_Stats_InspectQueue(t *testing.T) {
+	scanner := New(nil)
+	scanner.inspectChan <- &ScanResult{Bucket: "queued-1"}
+	scanner.inspectChan <- &ScanResult{Bucket: "queued-2"}
+
+	if got := scanner.Stats().InspectQueue; got != 2 {
+		t.Errorf("InspectQueue = %d, want %d", got, 2)
+	}
+}
+
 func TestScanner_Stats_Initial(t *testing.T) {
 	scanner := New(nil)
 	stats := scanner.Stats()
@@ -91,6 +132,24 @@ func TestScanner_Scan_EmptyList(t *testing.T) {
 	}
 }
 
+func TestScanner_ScanStream_Total(t *testing.T) {
+	scanner := New(&Config{Workers: 2, MaxRPS: 100, Timeout: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	names := make(chan string)
+	close(names)
+
+	for range scanner.ScanStream(ctx, names, 42) {
+		// Drain channel
+	}
+
+	if got := scanner.Stats().Total; got != 42 {
+		t.Errorf("Stats().Total = %d, want 42", got)
+	}
+}
+
 func TestScanner_Scan_ContextCanceled(t *testing.T) {
 	scanner := New(&Config{Workers: 2, MaxRPS: 100, Timeout: time.Second})
 
@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"path"
+	"strings"
+)
+
+// SensitiveKey is an object or archive entry name that likely holds secrets or data.
+type SensitiveKey struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// sensitiveNames match the full base name of a key.
+var sensitiveNames = map[string]string{
+	".env":              "credentials",
+	".htpasswd":         "credentials",
+	".netrc":            "credentials",
+	".pgpass":           "credentials",
+	".npmrc":            "credentials",
+	".dockercfg":        "credentials",
+	"credentials":       "credentials",
+	"credentials.json":  "credentials",
+	"secrets.json":      "credentials",
+	"secrets.yml":       "credentials",
+	"secrets.yaml":      "credentials",
+	"wp-config.php":     "config",
+	"web.config":        "config",
+	"config.php":        "config",
+	"settings.py":       "config",
+	"application.yml":   "config",
+	"id_rsa":            "private_key",
+	"id_dsa":            "private_key",
+	"id_ecdsa":          "private_key",
+	"id_ed25519":        "private_key",
+	"terraform.tfstate": "infrastructure",
+	"shadow":            "credentials",
+	"passwd":            "credentials",
+}
+
+// sensitiveExts match the key extension.
+var sensitiveExts = map[string]string{
+	".pem":      "private_key",
+	".key":      "private_key",
+	".p12":      "private_key",
+	".pfx":      "private_key",
+	".jks":      "private_key",
+	".kdbx":     "credentials",
+	".sql":      "database",
+	".dump":     "database",
+	".bak":      "backup",
+	".sqlite":   "database",
+	".sqlite3":  "database",
+	".db":       "database",
+	".mdb":      "database",
+	".tfstate":  "infrastructure",
+	".tfvars":   "infrastructure",
+	".ovpn":     "credentials",
+	".keystore": "private_key",
+}
+
+// sensitiveWords match anywhere in the lowercased base name.
+var sensitiveWords = []struct {
+	word     string
+	category string
+}{
+	{"password", "credentials"},
+	{"passwd", "credentials"},
+	{"secret", "credentials"},
+	{"credential", "credentials"},
+	{"private", "private_key"},
+	{"backup", "backup"},
+	{"dump", "database"},
+}
+
+// ClassifySensitiveKey returns the category of a sensitive-looking key, or ""
+// when the name looks harmless.
+func ClassifySensitiveKey(key string) string {
+	lower := strings.ToLower(strings.TrimSuffix(key, "/"))
+	if strings.Contains(lower, ".git/") || strings.HasSuffix(lower, ".git") {
+		return "source_control"
+	}
+
+	base := path.Base(lower)
+	if category, ok := sensitiveNames[base]; ok {
+		return category
+	}
+
+	// Compressed copies keep the inner extension (db.sql.gz)
+	inner := strings.TrimSuffix(strings.TrimSuffix(base, ".gz"), ".bz2")
+	if category, ok := sensitiveExts[path.Ext(inner)]; ok {
+		return category
+	}
+
+	for _, w := range sensitiveWords {
+		if strings.Contains(base, w.word) {
+			return w.category
+		}
+	}
+
+	return ""
+}
+
+// ClassifySensitiveKeys returns the sensitive names from a list.
+func ClassifySensitiveKeys(names []string) []SensitiveKey {
+	var found []SensitiveKey
+	for _, name := range names {
+		if category := ClassifySensitiveKey(name); category != "" {
+			found = append(found, SensitiveKey{Name: name, Category: category})
+		}
+	}
+	return found
+}
@@ -0,0 +1,40 @@
+package scanner
+
+import "testing"
+
+func TestClassifySensitiveKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{".env", "credentials"},
+		{"app/config/.env", "credentials"},
+		{"keys/id_rsa", "private_key"},
+		{"certs/server.pem", "private_key"},
+		{"db/users.sql", "database"},
+		{"db/users.sql.gz", "database"},
+		{"infra/terraform.tfstate", "infrastructure"},
+		{"repo/.git/config", "source_control"},
+		{"wp-config.php", "config"},
+		{"old-passwords.txt", "credentials"},
+		{"index.html", ""},
+		{"images/logo.png", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifySensitiveKey(tt.key); got != tt.expected {
+			t.Errorf("ClassifySensitiveKey(%q) = %q, want %q", tt.key, got, tt.expected)
+		}
+	}
+}
+
+func TestClassifySensitiveKeys(t *testing.T) {
+	found := ClassifySensitiveKeys([]string{"index.html", ".env", "logo.png", "dump.sql"})
+
+	if len(found) != 2 {
+		t.Fatalf("ClassifySensitiveKeys() found %d, want 2", len(found))
+	}
+	if found[0].Name != ".env" || found[1].Name != "dump.sql" {
+		t.Errorf("ClassifySensitiveKeys() = %+v, want .env and dump.sql in order", found)
+	}
+}